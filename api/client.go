@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 )
 
 var (
@@ -28,6 +30,7 @@ type Client struct {
 	baseUrl    string
 	httpClient *http.Client
 	paramMap   map[string]string
+	apiMapMu   sync.Mutex
 	apiMap     map[string]*APIDescription
 }
 
@@ -51,6 +54,36 @@ type ErrorEntry struct {
 	Path string `json:"path,omitempty"`
 }
 
+type RawResponse struct {
+	io.ReadCloser
+	StatusCode    int
+	ContentType   string
+	ContentLength int64
+	Header        http.Header
+}
+
+// IsPartial reports whether the server honored a Range request.
+func (r *RawResponse) IsPartial() bool {
+	return r.StatusCode == http.StatusPartialContent
+}
+
+// ContentRange parses the Content-Range header of a partial response.
+func (r *RawResponse) ContentRange() (start int64, end int64, size int64, ok bool) {
+	var sizeString string
+	n, err := fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%s", &start, &end, &sizeString)
+	if err != nil || n != 3 {
+		return 0, 0, 0, false
+	}
+	if sizeString == "*" {
+		return start, end, -1, true
+	}
+	size, err = strconv.ParseInt(sizeString, 10, 64)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return start, end, size, true
+}
+
 type APIDescription struct {
 	Path       string `json:"path"`
 	MinVersion int    `json:"minVersion"`
@@ -78,22 +111,90 @@ func (c *Client) SetParam(key string, value string) {
 }
 
 func (c *Client) Request(api string, version int, method string, paramMap map[string]string, response any) error {
-	apiDescription, ok := c.apiMap[api]
-	if !ok {
-		queryResponse, err := c.query(QueryRequest{
-			ApiNames: []string{api},
-		})
-		if err != nil {
-			return err
-		}
-		apiDescription = (*queryResponse.Data)[api]
-		c.apiMap[api] = apiDescription
+	apiDescription, err := c.getApiDescription(api)
+	if err != nil {
+		return err
 	}
 	return c.doRequest(apiDescription.Path, api, version, method, paramMap, response)
 }
 
-func (c *Client) RawRequest(api string, version int, method string, paramMap map[string]string) (io.ReadCloser, error) {
+func (c *Client) RawRequest(api string, version int, method string, paramMap map[string]string) (*RawResponse, error) {
+	return c.RawRequestWithHeader(api, version, method, paramMap, nil)
+}
+
+func (c *Client) RawRequestWithHeader(api string, version int, method string, paramMap map[string]string, header http.Header) (*RawResponse, error) {
+	apiDescription, err := c.getApiDescription(api)
+	if err != nil {
+		return nil, err
+	}
+	return c.doRawRequest(apiDescription.Path, api, version, method, paramMap, header)
+}
+
+// MultipartRequest sends paramMap as form fields followed by a single file
+// part read from r. The body is streamed, so r is never buffered in memory.
+func (c *Client) MultipartRequest(api string, version int, method string, paramMap map[string]string, fileField string, fileName string, r io.Reader, response any) error {
+	apiDescription, err := c.getApiDescription(api)
+	if err != nil {
+		return err
+	}
+
+	requestUrl, err := c.buildUrl(apiDescription.Path, api, version, method, nil)
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	multipartWriter := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(func() error {
+			fields := [][2]string{
+				{"api", api},
+				{"version", strconv.Itoa(version)},
+				{"method", method},
+			}
+			for k, v := range paramMap {
+				fields = append(fields, [2]string{k, v})
+			}
+			for _, field := range fields {
+				err := multipartWriter.WriteField(field[0], field[1])
+				if err != nil {
+					return err
+				}
+			}
+			partWriter, err := multipartWriter.CreateFormFile(fileField, fileName)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(partWriter, r)
+			if err != nil {
+				return err
+			}
+			return multipartWriter.Close()
+		}())
+	}()
+	defer func(pr *io.PipeReader) {
+		_ = pr.Close()
+	}(pr)
+
+	httpResponse, err := c.httpClient.Post(requestUrl.String(), multipartWriter.FormDataContentType(), pr)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(httpResponse.Body)
+	if httpResponse.StatusCode != 200 {
+		return fmt.Errorf("HTTP status code %d", httpResponse.StatusCode)
+	}
+
+	jsonDecoder := json.NewDecoder(httpResponse.Body)
+	return jsonDecoder.Decode(response)
+}
+
+func (c *Client) getApiDescription(api string) (*APIDescription, error) {
+	c.apiMapMu.Lock()
 	apiDescription, ok := c.apiMap[api]
+	c.apiMapMu.Unlock()
 	if !ok {
 		queryResponse, err := c.query(QueryRequest{
 			ApiNames: []string{api},
@@ -101,13 +202,18 @@ func (c *Client) RawRequest(api string, version int, method string, paramMap map
 		if err != nil {
 			return nil, err
 		}
-		apiDescription = (*queryResponse.Data)[api]
+		apiDescription, ok = (*queryResponse.Data)[api]
+		if !ok || apiDescription == nil {
+			return nil, &Error{Code: 102}
+		}
+		c.apiMapMu.Lock()
 		c.apiMap[api] = apiDescription
+		c.apiMapMu.Unlock()
 	}
-	return c.doRawRequest(apiDescription.Path, api, version, method, paramMap)
+	return apiDescription, nil
 }
 
-func (c *Client) doRawRequest(apiPath string, api string, version int, method string, paramMap map[string]string) (io.ReadCloser, error) {
+func (c *Client) buildUrl(apiPath string, api string, version int, method string, paramMap map[string]string) (*url.URL, error) {
 	baseUrl, err := url.Parse(c.baseUrl)
 	if err != nil {
 		return nil, err
@@ -127,22 +233,45 @@ func (c *Client) doRawRequest(apiPath string, api string, version int, method st
 	}
 	requestUrl.RawQuery = q.Encode()
 
-	httpResponse, err := c.httpClient.Get(requestUrl.String())
+	return requestUrl, nil
+}
+
+func (c *Client) doRawRequest(apiPath string, api string, version int, method string, paramMap map[string]string, header http.Header) (*RawResponse, error) {
+	requestUrl, err := c.buildUrl(apiPath, api, version, method, paramMap)
 	if err != nil {
 		return nil, err
 	}
-	if httpResponse.StatusCode != 200 {
+
+	httpRequest, err := http.NewRequest(http.MethodGet, requestUrl.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		httpRequest.Header[k] = v
+	}
+
+	httpResponse, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	if httpResponse.StatusCode != 200 && httpResponse.StatusCode != 206 {
 		defer func(Body io.ReadCloser) {
 			_ = Body.Close()
 		}(httpResponse.Body)
 		return nil, fmt.Errorf("HTTP status code %d", httpResponse.StatusCode)
 	}
 
-	return httpResponse.Body, nil
+	return &RawResponse{
+		ReadCloser:    httpResponse.Body,
+		StatusCode:    httpResponse.StatusCode,
+		ContentType:   httpResponse.Header.Get("Content-Type"),
+		ContentLength: httpResponse.ContentLength,
+		Header:        httpResponse.Header,
+	}, nil
 }
 
 func (c *Client) doRequest(apiPath string, api string, version int, method string, paramMap map[string]string, response any) error {
-	r, err := c.doRawRequest(apiPath, api, version, method, paramMap)
+	r, err := c.doRawRequest(apiPath, api, version, method, paramMap, nil)
 	if err != nil {
 		return err
 	}
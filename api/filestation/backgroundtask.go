@@ -0,0 +1,98 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+	"strings"
+)
+
+type BackgroundTaskSortBy string
+
+const (
+	BackgroundTaskSortByCrtime   BackgroundTaskSortBy = "crtime"
+	BackgroundTaskSortByFinished BackgroundTaskSortBy = "finished"
+)
+
+type BackgroundTaskListRequest struct {
+	Offset        int
+	Limit         int
+	SortBy        BackgroundTaskSortBy
+	SortDirection SortDirection
+	ApiFilter     []string
+}
+
+type BackgroundTaskListResponse struct {
+	Total  int                  `json:"total"`
+	Offset int                  `json:"offset"`
+	Tasks  []BackgroundTaskInfo `json:"tasks,omitempty"`
+}
+
+type BackgroundTaskInfo struct {
+	Api            string          `json:"api,omitempty"`
+	Version        int             `json:"version"`
+	Method         string          `json:"method,omitempty"`
+	TaskId         string          `json:"taskid,omitempty"`
+	Finished       bool            `json:"finished"`
+	Params         json.RawMessage `json:"params,omitempty"`
+	Path           string          `json:"path,omitempty"`
+	ProcessedNum   int             `json:"processed_num"`
+	ProcessedSize  int64           `json:"processed_size"`
+	ProcessingPath string          `json:"processing_path,omitempty"`
+	Total          int64           `json:"total"`
+	Progress       float64         `json:"progress"`
+}
+
+type BackgroundTaskClearFinishedRequest struct {
+	TaskId []string
+}
+
+type BackgroundTaskClearFinishedResponse struct{}
+
+func (a *Api) BackgroundTaskList(req BackgroundTaskListRequest) (*api.Response[BackgroundTaskListResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["offset"] = strconv.Itoa(req.Offset)
+	if req.Limit != 0 {
+		paramMap["limit"] = strconv.Itoa(req.Limit)
+	}
+	if req.SortBy != "" {
+		paramMap["sort_by"] = string(req.SortBy)
+	}
+	if req.SortDirection != "" {
+		paramMap["sort_direction"] = string(req.SortDirection)
+	}
+	if len(req.ApiFilter) > 0 {
+		paramMap["api_filter"] = strings.Join(req.ApiFilter, ",")
+	}
+
+	var res api.Response[BackgroundTaskListResponse]
+	err := a.client.Request("SYNO.FileStation.BackgroundTask", 3, "list", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) BackgroundTaskClearFinished(req BackgroundTaskClearFinishedRequest) (*api.Response[BackgroundTaskClearFinishedResponse], error) {
+	paramMap := make(map[string]string)
+	if len(req.TaskId) > 0 {
+		jsonBytes, err := json.Marshal(req.TaskId)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["taskid"] = string(jsonBytes)
+	}
+
+	var res api.Response[BackgroundTaskClearFinishedResponse]
+	err := a.client.Request("SYNO.FileStation.BackgroundTask", 3, "clear_finished", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
@@ -0,0 +1,104 @@
+package filestation
+
+import (
+	"errors"
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+)
+
+type CheckPermissionRequest struct {
+	Path       string
+	Filename   string
+	Overwrite  bool
+	CreateOnly bool
+}
+
+type CheckPermissionResponse struct {
+	Skipped bool   `json:"blSkip"`
+	File    string `json:"file,omitempty"`
+}
+
+type WritePermission struct {
+	// Allowed reports whether the session may create the file.
+	Allowed bool
+	// Exists reports whether creating the file would overwrite an existing one.
+	Exists bool
+}
+
+func (a *Api) CheckPermission(req CheckPermissionRequest) (*api.Response[CheckPermissionResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["path"] = req.Path
+	paramMap["filename"] = req.Filename
+	paramMap["overwrite"] = strconv.FormatBool(req.Overwrite)
+	paramMap["create_only"] = strconv.FormatBool(req.CreateOnly)
+
+	var res api.Response[CheckPermissionResponse]
+	err := a.client.Request("SYNO.FileStation.CheckPermission", 3, "write", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+// CheckWritePermission checks whether filename can be created in the folder
+// path, and whether doing so would overwrite an existing file.
+func (a *Api) CheckWritePermission(path string, filename string) (*WritePermission, error) {
+	_, err := a.CheckPermission(CheckPermissionRequest{
+		Path:       path,
+		Filename:   filename,
+		CreateOnly: true,
+	})
+	if err == nil {
+		return &WritePermission{
+			Allowed: true,
+		}, nil
+	}
+	if isPermissionDenied(err) {
+		return &WritePermission{}, nil
+	}
+	if !isAlreadyExists(err) {
+		return nil, err
+	}
+
+	_, err = a.CheckPermission(CheckPermissionRequest{
+		Path:      path,
+		Filename:  filename,
+		Overwrite: true,
+	})
+	if err == nil {
+		return &WritePermission{
+			Allowed: true,
+			Exists:  true,
+		}, nil
+	}
+	if isPermissionDenied(err) {
+		return &WritePermission{
+			Exists: true,
+		}, nil
+	}
+	return nil, err
+}
+
+func errorCode(err error) int {
+	var apiError *api.Error
+	if errors.As(err, &apiError) {
+		return apiError.Code
+	}
+	return 0
+}
+
+func isPermissionDenied(err error) bool {
+	switch errorCode(err) {
+	case 105, 403, 404, 405, 407:
+		return true
+	default:
+		return false
+	}
+}
+
+func isAlreadyExists(err error) bool {
+	return errorCode(err) == 414
+}
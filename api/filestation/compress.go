@@ -0,0 +1,97 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+)
+
+type CompressLevel string
+
+const (
+	CompressLevelModerate CompressLevel = "moderate"
+	CompressLevelStore    CompressLevel = "store"
+	CompressLevelFastest  CompressLevel = "fastest"
+	CompressLevelBest     CompressLevel = "best"
+)
+
+type CompressMode string
+
+const (
+	CompressModeAdd         CompressMode = "add"
+	CompressModeUpdate      CompressMode = "update"
+	CompressModeRefreshen   CompressMode = "refreshen"
+	CompressModeSynchronize CompressMode = "synchronize"
+)
+
+type CompressFormat string
+
+const (
+	CompressFormatZip CompressFormat = "zip"
+	CompressFormat7z  CompressFormat = "7z"
+)
+
+type CompressRequest struct {
+	Path         []string
+	DestFilePath string
+	Level        CompressLevel
+	Mode         CompressMode
+	Format       CompressFormat
+	Password     string
+}
+
+type CompressStartResponse struct {
+	TaskId string `json:"taskid"`
+}
+
+type CompressStatusResponse struct {
+	DestFilePath string `json:"dest_file_path,omitempty"`
+	Finished     bool   `json:"finished"`
+}
+
+type CompressTask struct {
+	*backgroundTask[CompressStatusResponse]
+}
+
+func (a *Api) Compress(req CompressRequest) (*CompressTask, error) {
+	paramMap := make(map[string]string)
+	if len(req.Path) > 0 {
+		jsonBytes, err := json.Marshal(req.Path)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["path"] = string(jsonBytes)
+	}
+	paramMap["dest_file_path"] = req.DestFilePath
+	if req.Level != "" {
+		paramMap["level"] = string(req.Level)
+	}
+	if req.Mode != "" {
+		paramMap["mode"] = string(req.Mode)
+	}
+	if req.Format != "" {
+		paramMap["format"] = string(req.Format)
+	}
+	if req.Password != "" {
+		paramMap["password"] = req.Password
+	}
+
+	var res api.Response[CompressStartResponse]
+	err := a.client.Request("SYNO.FileStation.Compress", 3, "start", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.Data == nil {
+		return nil, errNoTaskId
+	}
+	return &CompressTask{
+		backgroundTask: newBackgroundTask(a.client, "SYNO.FileStation.Compress", 3, res.Data.TaskId, func(status *CompressStatusResponse) TaskStatus {
+			return TaskStatus{
+				Finished: status.Finished,
+				Progress: -1,
+			}
+		}),
+	}, nil
+}
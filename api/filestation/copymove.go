@@ -0,0 +1,77 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+)
+
+type CopyMoveRequest struct {
+	Path             []string
+	DestFolderPath   string
+	Overwrite        OverwriteMode
+	RemoveSrc        bool
+	AccurateProgress bool
+	SearchTaskId     string
+}
+
+type CopyMoveStartResponse struct {
+	TaskId string `json:"taskid"`
+}
+
+type CopyMoveStatusResponse struct {
+	DestFolderPath string  `json:"dest_folder_path,omitempty"`
+	Finished       bool    `json:"finished"`
+	Path           string  `json:"path,omitempty"`
+	ProcessedSize  int64   `json:"processed_size"`
+	ProcessingFile string  `json:"processing_file,omitempty"`
+	Progress       float64 `json:"progress"`
+	Total          int64   `json:"total"`
+}
+
+type CopyMoveTask struct {
+	*backgroundTask[CopyMoveStatusResponse]
+}
+
+func (a *Api) CopyMove(req CopyMoveRequest) (*CopyMoveTask, error) {
+	paramMap := make(map[string]string)
+	if len(req.Path) > 0 {
+		jsonBytes, err := json.Marshal(req.Path)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["path"] = string(jsonBytes)
+	}
+	paramMap["dest_folder_path"] = req.DestFolderPath
+	switch req.Overwrite {
+	case OverwriteOverwrite:
+		paramMap["overwrite"] = "true"
+	case OverwriteSkip:
+		paramMap["overwrite"] = "false"
+	}
+	paramMap["remove_src"] = strconv.FormatBool(req.RemoveSrc)
+	paramMap["accurate_progress"] = strconv.FormatBool(req.AccurateProgress)
+	if req.SearchTaskId != "" {
+		paramMap["search_taskid"] = req.SearchTaskId
+	}
+
+	var res api.Response[CopyMoveStartResponse]
+	err := a.client.Request("SYNO.FileStation.CopyMove", 3, "start", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.Data == nil {
+		return nil, errNoTaskId
+	}
+	return &CopyMoveTask{
+		backgroundTask: newBackgroundTask(a.client, "SYNO.FileStation.CopyMove", 3, res.Data.TaskId, func(status *CopyMoveStatusResponse) TaskStatus {
+			return TaskStatus{
+				Finished: status.Finished,
+				Progress: status.Progress,
+			}
+		}),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+)
+
+type CreateFolderRequest struct {
+	FolderPath  []string
+	Name        []string
+	ForceParent bool
+	Additional  []AdditionalField
+}
+
+type CreateFolderResponse struct {
+	Folders []File `json:"folders,omitempty"`
+}
+
+func (a *Api) CreateFolder(req CreateFolderRequest) (*api.Response[CreateFolderResponse], error) {
+	paramMap := make(map[string]string)
+	if len(req.FolderPath) > 0 {
+		jsonBytes, err := json.Marshal(req.FolderPath)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["folder_path"] = string(jsonBytes)
+	}
+	if len(req.Name) > 0 {
+		jsonBytes, err := json.Marshal(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["name"] = string(jsonBytes)
+	}
+	if req.ForceParent {
+		paramMap["force_parent"] = strconv.FormatBool(req.ForceParent)
+	}
+	if len(req.Additional) > 0 {
+		jsonBytes, err := json.Marshal(req.Additional)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["additional"] = string(jsonBytes)
+	}
+
+	var res api.Response[CreateFolderResponse]
+	err := a.client.Request("SYNO.FileStation.CreateFolder", 2, "create", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
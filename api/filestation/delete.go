@@ -0,0 +1,100 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+)
+
+type DeleteRequest struct {
+	Path         []string
+	Recursive    bool
+	SearchTaskId string
+}
+
+type DeleteResponse struct{}
+
+type DeleteStartRequest struct {
+	Path             []string
+	AccurateProgress bool
+	Recursive        bool
+	SearchTaskId     string
+}
+
+type DeleteStartResponse struct {
+	TaskId string `json:"taskid"`
+}
+
+type DeleteStatusResponse struct {
+	Finished       bool    `json:"finished"`
+	Path           string  `json:"path,omitempty"`
+	ProcessedNum   int     `json:"processed_num"`
+	ProcessingPath string  `json:"processing_path,omitempty"`
+	Progress       float64 `json:"progress"`
+	Total          int     `json:"total"`
+}
+
+type DeleteTask struct {
+	*backgroundTask[DeleteStatusResponse]
+}
+
+func (a *Api) Delete(req DeleteRequest) (*api.Response[DeleteResponse], error) {
+	paramMap := make(map[string]string)
+	if len(req.Path) > 0 {
+		jsonBytes, err := json.Marshal(req.Path)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["path"] = string(jsonBytes)
+	}
+	paramMap["recursive"] = strconv.FormatBool(req.Recursive)
+	if req.SearchTaskId != "" {
+		paramMap["search_taskid"] = req.SearchTaskId
+	}
+
+	var res api.Response[DeleteResponse]
+	err := a.client.Request("SYNO.FileStation.Delete", 2, "delete", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) DeleteStart(req DeleteStartRequest) (*DeleteTask, error) {
+	paramMap := make(map[string]string)
+	if len(req.Path) > 0 {
+		jsonBytes, err := json.Marshal(req.Path)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["path"] = string(jsonBytes)
+	}
+	paramMap["accurate_progress"] = strconv.FormatBool(req.AccurateProgress)
+	paramMap["recursive"] = strconv.FormatBool(req.Recursive)
+	if req.SearchTaskId != "" {
+		paramMap["search_taskid"] = req.SearchTaskId
+	}
+
+	var res api.Response[DeleteStartResponse]
+	err := a.client.Request("SYNO.FileStation.Delete", 2, "start", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.Data == nil {
+		return nil, errNoTaskId
+	}
+	return &DeleteTask{
+		backgroundTask: newBackgroundTask(a.client, "SYNO.FileStation.Delete", 2, res.Data.TaskId, func(status *DeleteStatusResponse) TaskStatus {
+			return TaskStatus{
+				Finished: status.Finished,
+				Progress: status.Progress,
+			}
+		}),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+)
+
+type DirSizeRequest struct {
+	Path []string
+}
+
+type DirSizeStartResponse struct {
+	TaskId string `json:"taskid"`
+}
+
+type DirSizeStatusResponse struct {
+	Finished  bool  `json:"finished"`
+	NumDir    int   `json:"num_dir"`
+	NumFile   int   `json:"num_file"`
+	TotalSize int64 `json:"total_size"`
+}
+
+type DirSizeTask struct {
+	*backgroundTask[DirSizeStatusResponse]
+}
+
+func (a *Api) DirSize(req DirSizeRequest) (*DirSizeTask, error) {
+	paramMap := make(map[string]string)
+	if len(req.Path) > 0 {
+		jsonBytes, err := json.Marshal(req.Path)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["path"] = string(jsonBytes)
+	}
+
+	var res api.Response[DirSizeStartResponse]
+	err := a.client.Request("SYNO.FileStation.DirSize", 2, "start", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.Data == nil {
+		return nil, errNoTaskId
+	}
+	return &DirSizeTask{
+		backgroundTask: newBackgroundTask(a.client, "SYNO.FileStation.DirSize", 2, res.Data.TaskId, func(status *DirSizeStatusResponse) TaskStatus {
+			return TaskStatus{
+				Finished: status.Finished,
+				Progress: -1,
+			}
+		}),
+	}, nil
+}
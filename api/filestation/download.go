@@ -0,0 +1,161 @@
+package filestation
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ngyewch/go-syno/api"
+	"io"
+	"io/fs"
+	"os"
+)
+
+const (
+	partialFileSuffix     = ".part"
+	partialMetadataSuffix = ".part.json"
+)
+
+type partialMetadata struct {
+	Path  string `json:"path"`
+	Size  int64  `json:"size"`
+	Mtime int64  `json:"mtime"`
+}
+
+// DownloadFile downloads remotePath to localPath. Data is written to
+// localPath+".part" and renamed into place once complete. If an earlier call
+// was interrupted, the download is resumed with a Range request, provided the
+// remote file's size and mtime have not changed since it was started.
+func (a *Api) DownloadFile(remotePath string, localPath string) error {
+	getInfoResponse, err := a.GetInfo(GetInfoRequest{
+		Path:       []string{remotePath},
+		Additional: []AdditionalField{AdditionalSize, AdditionalTime},
+	})
+	if err != nil {
+		return err
+	}
+	if getInfoResponse.Data == nil || len(getInfoResponse.Data.Files) == 0 || getInfoResponse.Data.Files[0].Additional == nil {
+		return fs.ErrNotExist
+	}
+	file := getInfoResponse.Data.Files[0]
+	if file.IsDir {
+		return errors.New("cannot resume download of a directory")
+	}
+	metadata := partialMetadata{
+		Path: remotePath,
+		Size: file.Additional.Size,
+	}
+	if file.Additional.Time != nil {
+		metadata.Mtime = file.Additional.Time.Mtime
+	}
+
+	partialPath := localPath + partialFileSuffix
+	metadataPath := localPath + partialMetadataSuffix
+
+	offset := resumeOffset(partialPath, metadataPath, metadata)
+	if offset == 0 {
+		err = writePartialMetadata(metadataPath, metadata)
+		if err != nil {
+			return err
+		}
+	}
+
+	r, err := a.Download(DownloadRequest{
+		Path:   []string{remotePath},
+		Mode:   DownloadModeDownload,
+		Offset: offset,
+	})
+	if err != nil {
+		return err
+	}
+	if offset > 0 && r.IsPartial() && !rangeStartsAt(r, offset, metadata.Size) {
+		// the server did not resume where the partial file ends, start over
+		_ = r.Close()
+		offset = 0
+		err = writePartialMetadata(metadataPath, metadata)
+		if err != nil {
+			return err
+		}
+		r, err = a.Download(DownloadRequest{
+			Path: []string{remotePath},
+			Mode: DownloadModeDownload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	defer func(r io.ReadCloser) {
+		_ = r.Close()
+	}(r)
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset > 0 && r.IsPartial() {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+	w, err := os.OpenFile(partialPath, flags, 0666)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, r)
+	if err != nil {
+		_ = w.Close()
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	fileInfo, err := os.Stat(partialPath)
+	if err != nil {
+		return err
+	}
+	if fileInfo.Size() != metadata.Size {
+		return io.ErrUnexpectedEOF
+	}
+
+	err = os.Rename(partialPath, localPath)
+	if err != nil {
+		return err
+	}
+	return os.Remove(metadataPath)
+}
+
+// rangeStartsAt reports whether a partial response starts at offset of a file
+// of the given size.
+func rangeStartsAt(r *api.RawResponse, offset int64, size int64) bool {
+	start, _, total, ok := r.ContentRange()
+	if !ok || start != offset {
+		return false
+	}
+	return total < 0 || total == size
+}
+
+func resumeOffset(partialPath string, metadataPath string, metadata partialMetadata) int64 {
+	fileInfo, err := os.Stat(partialPath)
+	if err != nil {
+		return 0
+	}
+	jsonBytes, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return 0
+	}
+	var previous partialMetadata
+	err = json.Unmarshal(jsonBytes, &previous)
+	if err != nil || previous != metadata {
+		return 0
+	}
+	if fileInfo.Size() >= metadata.Size {
+		return 0
+	}
+	return fileInfo.Size()
+}
+
+func writePartialMetadata(metadataPath string, metadata partialMetadata) error {
+	jsonBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(metadataPath, jsonBytes, 0666)
+}
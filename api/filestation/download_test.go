@@ -0,0 +1,194 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/internal/fakedsm"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testContent = strings.Repeat("0123456789abcdef", 1024)
+
+func newTestApi(t *testing.T, files map[string]string) (*Api, *fakedsm.Server) {
+	s := fakedsm.New(files)
+	t.Cleanup(s.Close)
+	client, err := api.NewClient(s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(client), s
+}
+
+// writePartial leaves behind an interrupted download of n bytes of filler, so
+// that resumed downloads can be told apart from restarted ones.
+func writePartial(t *testing.T, localPath string, n int, metadata partialMetadata) {
+	err := os.WriteFile(localPath+partialFileSuffix, []byte(strings.Repeat("x", n)), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonBytes, err := json.Marshal(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(localPath+partialMetadataSuffix, jsonBytes, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertDownloaded(t *testing.T, localPath string, want string) {
+	t.Helper()
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		i := 0
+		for i < len(data) && i < len(want) && data[i] == want[i] {
+			i++
+		}
+		t.Fatalf("got %d bytes, want %d bytes, first difference at %d", len(data), len(want), i)
+	}
+	for _, suffix := range []string{partialFileSuffix, partialMetadataSuffix} {
+		_, err = os.Stat(localPath + suffix)
+		if !os.IsNotExist(err) {
+			t.Fatalf("%s left behind", localPath+suffix)
+		}
+	}
+}
+
+func TestDownloadFile(t *testing.T) {
+	a, _ := newTestApi(t, map[string]string{
+		"/share/file.bin": testContent,
+	})
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+
+	err := a.DownloadFile("/share/file.bin", localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDownloaded(t, localPath, testContent)
+}
+
+func TestDownloadFileInterrupted(t *testing.T) {
+	a, s := newTestApi(t, map[string]string{
+		"/share/file.bin": testContent,
+	})
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+
+	s.SetTruncate("/share/file.bin", 5000)
+	err := a.DownloadFile("/share/file.bin", localPath)
+	if err == nil {
+		t.Fatal("expected an interrupted download to fail")
+	}
+	fileInfo, err := os.Stat(localPath + partialFileSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fileInfo.Size() != 5000 {
+		t.Fatalf("got %d partial bytes, want 5000", fileInfo.Size())
+	}
+
+	s.SetTruncate("/share/file.bin", -1)
+	err = a.DownloadFile("/share/file.bin", localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDownloaded(t, localPath, testContent)
+}
+
+func TestDownloadFileResume(t *testing.T) {
+	a, _ := newTestApi(t, map[string]string{
+		"/share/file.bin": testContent,
+	})
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+	writePartial(t, localPath, 1000, partialMetadata{
+		Path:  "/share/file.bin",
+		Size:  int64(len(testContent)),
+		Mtime: fakedsm.DefaultMtime.Unix(),
+	})
+
+	err := a.DownloadFile("/share/file.bin", localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the filler is kept, so only the remainder was downloaded
+	assertDownloaded(t, localPath, strings.Repeat("x", 1000)+testContent[1000:])
+}
+
+func TestDownloadFileResumeBadRange(t *testing.T) {
+	a, s := newTestApi(t, map[string]string{
+		"/share/file.bin": testContent,
+	})
+	s.SetBadRange(true)
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+	writePartial(t, localPath, 1000, partialMetadata{
+		Path:  "/share/file.bin",
+		Size:  int64(len(testContent)),
+		Mtime: fakedsm.DefaultMtime.Unix(),
+	})
+
+	err := a.DownloadFile("/share/file.bin", localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDownloaded(t, localPath, testContent)
+}
+
+func TestDownloadFileResumeNoRange(t *testing.T) {
+	a, s := newTestApi(t, map[string]string{
+		"/share/file.bin": testContent,
+	})
+	s.SetNoRange(true)
+	localPath := filepath.Join(t.TempDir(), "file.bin")
+	writePartial(t, localPath, 1000, partialMetadata{
+		Path:  "/share/file.bin",
+		Size:  int64(len(testContent)),
+		Mtime: fakedsm.DefaultMtime.Unix(),
+	})
+
+	err := a.DownloadFile("/share/file.bin", localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDownloaded(t, localPath, testContent)
+}
+
+func TestDownloadFileChanged(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(s *fakedsm.Server)
+	}{
+		{"size", func(s *fakedsm.Server) {
+			s.WriteFile("/share/file.bin", testContent+"more")
+		}},
+		{"mtime", func(s *fakedsm.Server) {
+			s.SetMtime("/share/file.bin", fakedsm.DefaultMtime.Add(time.Hour))
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, s := newTestApi(t, map[string]string{
+				"/share/file.bin": testContent,
+			})
+			localPath := filepath.Join(t.TempDir(), "file.bin")
+			writePartial(t, localPath, 1000, partialMetadata{
+				Path:  "/share/file.bin",
+				Size:  int64(len(testContent)),
+				Mtime: fakedsm.DefaultMtime.Unix(),
+			})
+			test.change(s)
+
+			err := a.DownloadFile("/share/file.bin", localPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, _ := s.ReadFile("/share/file.bin")
+			assertDownloaded(t, localPath, content)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package filestation
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+	"strings"
+)
+
+type ExtractRequest struct {
+	FilePath        string
+	DestFolderPath  string
+	Overwrite       bool
+	KeepDir         bool
+	CreateSubfolder bool
+	Codepage        string
+	Password        string
+	ItemId          []int
+}
+
+type ExtractStartResponse struct {
+	TaskId string `json:"taskid"`
+}
+
+type ExtractStatusResponse struct {
+	DestFolderPath string  `json:"dest_folder_path,omitempty"`
+	Finished       bool    `json:"finished"`
+	Progress       float64 `json:"progress"`
+}
+
+type ExtractTask struct {
+	*backgroundTask[ExtractStatusResponse]
+}
+
+type ExtractSortBy string
+
+const (
+	ExtractSortByName     ExtractSortBy = "name"
+	ExtractSortBySize     ExtractSortBy = "size"
+	ExtractSortByPackSize ExtractSortBy = "pack_size"
+	ExtractSortByMtime    ExtractSortBy = "mtime"
+)
+
+type ExtractListRequest struct {
+	FilePath      string
+	Offset        int
+	Limit         int
+	SortBy        ExtractSortBy
+	SortDirection SortDirection
+	Codepage      string
+	Password      string
+	ItemId        int
+}
+
+type ExtractListResponse struct {
+	Total int           `json:"total"`
+	Items []ArchiveItem `json:"items,omitempty"`
+}
+
+type ArchiveItem struct {
+	ItemId   int           `json:"itemid"`
+	Name     string        `json:"name,omitempty"`
+	Path     string        `json:"path,omitempty"`
+	Size     int64         `json:"size"`
+	PackSize int64         `json:"pack_size"`
+	Mtime    string        `json:"mtime,omitempty"`
+	IsDir    bool          `json:"is_dir"`
+	Items    []ArchiveItem `json:"items,omitempty"`
+}
+
+func (a *Api) Extract(req ExtractRequest) (*ExtractTask, error) {
+	paramMap := make(map[string]string)
+	paramMap["file_path"] = req.FilePath
+	paramMap["dest_folder_path"] = req.DestFolderPath
+	paramMap["overwrite"] = strconv.FormatBool(req.Overwrite)
+	paramMap["keep_dir"] = strconv.FormatBool(req.KeepDir)
+	paramMap["create_subfolder"] = strconv.FormatBool(req.CreateSubfolder)
+	if req.Codepage != "" {
+		paramMap["codepage"] = req.Codepage
+	}
+	if req.Password != "" {
+		paramMap["password"] = req.Password
+	}
+	if len(req.ItemId) > 0 {
+		var itemIds []string
+		for _, itemId := range req.ItemId {
+			itemIds = append(itemIds, strconv.Itoa(itemId))
+		}
+		paramMap["item_id"] = strings.Join(itemIds, ",")
+	}
+
+	var res api.Response[ExtractStartResponse]
+	err := a.client.Request("SYNO.FileStation.Extract", 2, "start", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.Data == nil {
+		return nil, errNoTaskId
+	}
+	return &ExtractTask{
+		backgroundTask: newBackgroundTask(a.client, "SYNO.FileStation.Extract", 2, res.Data.TaskId, func(status *ExtractStatusResponse) TaskStatus {
+			return TaskStatus{
+				Finished: status.Finished,
+				Progress: status.Progress,
+			}
+		}),
+	}, nil
+}
+
+func (a *Api) ExtractList(req ExtractListRequest) (*api.Response[ExtractListResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["file_path"] = req.FilePath
+	paramMap["offset"] = strconv.Itoa(req.Offset)
+	if req.Limit != 0 {
+		paramMap["limit"] = strconv.Itoa(req.Limit)
+	}
+	if req.SortBy != "" {
+		paramMap["sort_by"] = string(req.SortBy)
+	}
+	if req.SortDirection != "" {
+		paramMap["sort_direction"] = string(req.SortDirection)
+	}
+	if req.Codepage != "" {
+		paramMap["codepage"] = req.Codepage
+	}
+	if req.Password != "" {
+		paramMap["password"] = req.Password
+	}
+	if req.ItemId != 0 {
+		paramMap["item_id"] = strconv.Itoa(req.ItemId)
+	}
+
+	var res api.Response[ExtractListResponse]
+	err := a.client.Request("SYNO.FileStation.Extract", 2, "list", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
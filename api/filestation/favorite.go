@@ -0,0 +1,142 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+)
+
+type Favorite struct {
+	Path       string          `json:"path,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Status     string          `json:"status,omitempty"`
+	Additional *FileAdditional `json:"additional,omitempty"`
+}
+
+type FavoriteListRequest struct {
+	Offset       int
+	Limit        int
+	StatusFilter string
+	Additional   []AdditionalField
+}
+
+type FavoriteListResponse struct {
+	Total     int        `json:"total"`
+	Offset    int        `json:"offset"`
+	Favorites []Favorite `json:"favorites,omitempty"`
+}
+
+type FavoriteAddRequest struct {
+	Path  string
+	Name  string
+	Index int
+}
+
+type FavoriteAddResponse struct{}
+
+type FavoriteEditRequest struct {
+	Path string
+	Name string
+}
+
+type FavoriteEditResponse struct{}
+
+type FavoriteDeleteRequest struct {
+	Path string
+}
+
+type FavoriteDeleteResponse struct{}
+
+type FavoriteClearBrokenResponse struct{}
+
+func (a *Api) FavoriteList(req FavoriteListRequest) (*api.Response[FavoriteListResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["offset"] = strconv.Itoa(req.Offset)
+	if req.Limit != 0 {
+		paramMap["limit"] = strconv.Itoa(req.Limit)
+	}
+	if req.StatusFilter != "" {
+		paramMap["status_filter"] = req.StatusFilter
+	}
+	if len(req.Additional) > 0 {
+		jsonBytes, err := json.Marshal(req.Additional)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["additional"] = string(jsonBytes)
+	}
+
+	var res api.Response[FavoriteListResponse]
+	err := a.client.Request("SYNO.FileStation.Favorite", 2, "list", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) FavoriteAdd(req FavoriteAddRequest) (*api.Response[FavoriteAddResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["path"] = req.Path
+	paramMap["name"] = req.Name
+	if req.Index != 0 {
+		paramMap["index"] = strconv.Itoa(req.Index)
+	}
+
+	var res api.Response[FavoriteAddResponse]
+	err := a.client.Request("SYNO.FileStation.Favorite", 2, "add", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) FavoriteEdit(req FavoriteEditRequest) (*api.Response[FavoriteEditResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["path"] = req.Path
+	paramMap["name"] = req.Name
+
+	var res api.Response[FavoriteEditResponse]
+	err := a.client.Request("SYNO.FileStation.Favorite", 2, "edit", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) FavoriteDelete(req FavoriteDeleteRequest) (*api.Response[FavoriteDeleteResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["path"] = req.Path
+
+	var res api.Response[FavoriteDeleteResponse]
+	err := a.client.Request("SYNO.FileStation.Favorite", 2, "delete", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) FavoriteClearBroken() (*api.Response[FavoriteClearBrokenResponse], error) {
+	paramMap := make(map[string]string)
+
+	var res api.Response[FavoriteClearBrokenResponse]
+	err := a.client.Request("SYNO.FileStation.Favorite", 2, "clear_broken", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
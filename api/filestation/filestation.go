@@ -2,8 +2,9 @@ package filestation
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/ngyewch/go-syno/api"
-	"io"
+	"net/http"
 	"strconv"
 	"strings"
 )
@@ -43,10 +44,10 @@ type Api struct {
 type ListShareRequest struct {
 	Offset        int
 	Limit         int
-	SortBy        string
-	SortDirection string
+	SortBy        SortBy
+	SortDirection SortDirection
 	OnlyWritable  bool
-	Additional    []string
+	Additional    []AdditionalField
 }
 
 type ListShareResponse struct {
@@ -59,28 +60,39 @@ type ListRequest struct {
 	FolderPath    string
 	Offset        int
 	Limit         int
-	SortBy        string
-	SortDirection string
+	SortBy        SortBy
+	SortDirection SortDirection
 	Pattern       []string
-	FileType      string
+	FileType      FileType
 	GotoPath      string
-	Additional    []string
+	Additional    []AdditionalField
 }
 
 type ListResponse Folder
 
 type GetInfoRequest struct {
 	Path       []string
-	Additional []string
+	Additional []AdditionalField
 }
 
 type GetInfoResponse struct {
 	Files []File `json:"files,omitempty"`
 }
 
+type DownloadMode string
+
+const (
+	DownloadModeOpen     DownloadMode = "open"
+	DownloadModeDownload DownloadMode = "download"
+)
+
 type DownloadRequest struct {
 	Path []string
-	Mode string
+	Mode DownloadMode
+	// Offset and Length select a byte range; a Length of 0 reads to the end.
+	// Use RawResponse.IsPartial to check whether the server honored the range.
+	Offset int64
+	Length int64
 }
 
 type Folder struct {
@@ -98,13 +110,14 @@ type File struct {
 }
 
 type FileAdditional struct {
-	RealPath       string       `json:"real_path,omitempty"`
-	Size           int64        `json:"size"`
-	Owner          *Owner       `json:"owner,omitempty"`
-	Time           *Time        `json:"time,omitempty"`
-	Perm           *Permissions `json:"perm,omitempty"`
-	MountPointType string       `json:"mount_point_type,omitempty"`
-	Type           string       `json:"type,omitempty"`
+	RealPath       string        `json:"real_path,omitempty"`
+	Size           int64         `json:"size"`
+	Owner          *Owner        `json:"owner,omitempty"`
+	Time           *Time         `json:"time,omitempty"`
+	Perm           *Permissions  `json:"perm,omitempty"`
+	MountPointType string        `json:"mount_point_type,omitempty"`
+	Type           string        `json:"type,omitempty"`
+	VolumeStatus   *VolumeStatus `json:"volume_status,omitempty"`
 }
 
 type Permissions struct {
@@ -116,6 +129,7 @@ type Permissions struct {
 type SharedFolder struct {
 	Path       string                  `json:"path,omitempty"`
 	Name       string                  `json:"name,omitempty"`
+	IsDir      bool                    `json:"isdir"`
 	Additional *SharedFolderAdditional `json:"additional,omitempty"`
 }
 
@@ -126,6 +140,7 @@ type SharedFolderAdditional struct {
 	Perm           *SharedFolderPermissions `json:"perm,omitempty"`
 	MountPointType string                   `json:"mount_point_type,omitempty"`
 	VolumeStatus   *VolumeStatus            `json:"volume_status,omitempty"`
+	SyncShare      bool                     `json:"sync_share"`
 }
 
 type Owner struct {
@@ -184,10 +199,10 @@ func (a *Api) ListShare(req ListShareRequest) (*api.Response[ListShareResponse],
 		paramMap["limit"] = strconv.Itoa(req.Limit)
 	}
 	if req.SortBy != "" {
-		paramMap["sort_by"] = req.SortBy
+		paramMap["sort_by"] = string(req.SortBy)
 	}
 	if req.SortDirection != "" {
-		paramMap["sort_direction"] = req.SortDirection
+		paramMap["sort_direction"] = string(req.SortDirection)
 	}
 	if req.OnlyWritable {
 		paramMap["onlywritable"] = strconv.FormatBool(req.OnlyWritable)
@@ -221,16 +236,16 @@ func (a *Api) List(req ListRequest) (*api.Response[ListResponse], error) {
 		paramMap["limit"] = strconv.Itoa(req.Limit)
 	}
 	if req.SortBy != "" {
-		paramMap["sort_by"] = req.SortBy
+		paramMap["sort_by"] = string(req.SortBy)
 	}
 	if req.SortDirection != "" {
-		paramMap["sort_direction"] = req.SortDirection
+		paramMap["sort_direction"] = string(req.SortDirection)
 	}
 	if len(req.Pattern) > 0 {
 		paramMap["pattern"] = strings.Join(req.Pattern, ",")
 	}
 	if req.FileType != "" {
-		paramMap["filetype"] = req.FileType
+		paramMap["filetype"] = string(req.FileType)
 	}
 	if req.GotoPath != "" {
 		paramMap["goto_path"] = req.GotoPath
@@ -282,7 +297,7 @@ func (a *Api) GetInfo(req GetInfoRequest) (*api.Response[GetInfoResponse], error
 	return &res, nil
 }
 
-func (a *Api) Download(req DownloadRequest) (io.ReadCloser, error) {
+func (a *Api) Download(req DownloadRequest) (*api.RawResponse, error) {
 	paramMap := make(map[string]string)
 	if len(req.Path) > 0 {
 		jsonBytes, err := json.Marshal(req.Path)
@@ -292,7 +307,16 @@ func (a *Api) Download(req DownloadRequest) (io.ReadCloser, error) {
 		paramMap["path"] = string(jsonBytes)
 	}
 	if req.Mode != "" {
-		paramMap["mode"] = req.Mode
+		paramMap["mode"] = string(req.Mode)
+	}
+	var header http.Header
+	if req.Offset > 0 || req.Length > 0 {
+		header = make(http.Header)
+		if req.Length > 0 {
+			header.Set("Range", fmt.Sprintf("bytes=%d-%d", req.Offset, req.Offset+req.Length-1))
+		} else {
+			header.Set("Range", fmt.Sprintf("bytes=%d-", req.Offset))
+		}
 	}
-	return a.client.RawRequest("SYNO.FileStation.Download", 2, "download", paramMap)
+	return a.client.RawRequestWithHeader("SYNO.FileStation.Download", 2, "download", paramMap, header)
 }
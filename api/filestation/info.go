@@ -0,0 +1,61 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+	"strings"
+)
+
+type InfoResponse struct {
+	IsManager              bool             `json:"is_manager"`
+	SupportVirtualProtocol VirtualProtocols `json:"support_virtual_protocol,omitempty"`
+	SupportSharing         bool             `json:"support_sharing"`
+	Hostname               string           `json:"hostname,omitempty"`
+	Uid                    int              `json:"uid"`
+	Gid                    int              `json:"gid"`
+}
+
+// VirtualProtocols is the list of virtual file system protocols supported by
+// the server. DSM 6 reports it as a comma-separated string and DSM 7 as an
+// array; both are accepted.
+type VirtualProtocols []string
+
+func (v *VirtualProtocols) UnmarshalJSON(data []byte) error {
+	var protocols []string
+	err := json.Unmarshal(data, &protocols)
+	if err == nil {
+		*v = protocols
+		return nil
+	}
+	var s string
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	*v = nil
+	for _, protocol := range strings.Split(s, ",") {
+		protocol = strings.TrimSpace(protocol)
+		if protocol != "" {
+			*v = append(*v, protocol)
+		}
+	}
+	return nil
+}
+
+func (r *InfoResponse) VirtualProtocols() []string {
+	return r.SupportVirtualProtocol
+}
+
+func (a *Api) Info() (*api.Response[InfoResponse], error) {
+	paramMap := make(map[string]string)
+
+	var res api.Response[InfoResponse]
+	err := a.client.Request("SYNO.FileStation.Info", 2, "get", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
@@ -0,0 +1,103 @@
+package filestation
+
+const (
+	defaultPageSize = 1000
+)
+
+// Iterator pages through a list API transparently.
+type Iterator[T any] struct {
+	fetch    func(offset int, limit int) ([]T, int, error)
+	offset   int
+	pageSize int
+	total    int
+	items    []T
+	item     T
+	err      error
+	done     bool
+}
+
+func newIterator[T any](offset int, pageSize int, fetch func(offset int, limit int) ([]T, int, error)) *Iterator[T] {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	return &Iterator[T]{
+		fetch:    fetch,
+		offset:   offset,
+		pageSize: pageSize,
+	}
+}
+
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.items) == 0 {
+		if it.done {
+			return false
+		}
+		items, total, err := it.fetch(it.offset, it.pageSize)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.items = items
+		it.total = total
+		it.offset += len(items)
+		if len(items) == 0 || it.offset >= total {
+			it.done = true
+		}
+		if len(it.items) == 0 {
+			return false
+		}
+	}
+	it.item = it.items[0]
+	it.items = it.items[1:]
+	return true
+}
+
+func (it *Iterator[T]) Value() T {
+	return it.item
+}
+
+// Total returns the total number of items reported by the last page fetched.
+func (it *Iterator[T]) Total() int {
+	return it.total
+}
+
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// ListIter iterates over all files in req.FolderPath, starting at req.Offset
+// and fetching req.Limit files per page.
+func (a *Api) ListIter(req ListRequest) *Iterator[File] {
+	return newIterator(req.Offset, req.Limit, func(offset int, limit int) ([]File, int, error) {
+		req.Offset = offset
+		req.Limit = limit
+		listResponse, err := a.List(req)
+		if err != nil {
+			return nil, 0, err
+		}
+		if listResponse.Data == nil {
+			return nil, 0, errNoData
+		}
+		return listResponse.Data.Files, listResponse.Data.Total, nil
+	})
+}
+
+// ListShareIter iterates over all shared folders, starting at req.Offset and
+// fetching req.Limit shares per page.
+func (a *Api) ListShareIter(req ListShareRequest) *Iterator[SharedFolder] {
+	return newIterator(req.Offset, req.Limit, func(offset int, limit int) ([]SharedFolder, int, error) {
+		req.Offset = offset
+		req.Limit = limit
+		listShareResponse, err := a.ListShare(req)
+		if err != nil {
+			return nil, 0, err
+		}
+		if listShareResponse.Data == nil {
+			return nil, 0, errNoData
+		}
+		return listShareResponse.Data.Shares, listShareResponse.Data.Total, nil
+	})
+}
@@ -0,0 +1,23 @@
+//go:build go1.23
+
+package filestation
+
+import (
+	"iter"
+)
+
+// All returns a range-over-func sequence of the remaining items. An error
+// ends the sequence after being yielded with the zero value.
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next() {
+			if !yield(it.Value(), nil) {
+				return
+			}
+		}
+		if it.Err() != nil {
+			var zero T
+			yield(zero, it.Err())
+		}
+	}
+}
@@ -0,0 +1,47 @@
+//go:build go1.23
+
+package filestation
+
+import (
+	"errors"
+	"github.com/ngyewch/go-syno/api"
+	"strings"
+	"testing"
+)
+
+func TestIteratorAll(t *testing.T) {
+	a, _, names := newTestListApi(t, 7)
+	var got []string
+	for file, err := range a.ListIter(ListRequest{FolderPath: "/share/dir", Limit: 3, SortBy: SortByName}).All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, file.Name)
+	}
+	if strings.Join(got, ",") != strings.Join(names, ",") {
+		t.Fatalf("got %v, want %v", got, names)
+	}
+
+	got = nil
+	for file := range a.ListIter(ListRequest{FolderPath: "/share/dir", Limit: 3, SortBy: SortByName}).All() {
+		got = append(got, file.Name)
+		if len(got) == 4 {
+			break
+		}
+	}
+	if strings.Join(got, ",") != strings.Join(names[:4], ",") {
+		t.Fatalf("got %v, want %v", got, names[:4])
+	}
+
+	n := 0
+	for file, err := range a.ListIter(ListRequest{FolderPath: "/share/missing"}).All() {
+		n++
+		var apiError *api.Error
+		if !errors.As(err, &apiError) || apiError.Code != 408 || file.Name != "" {
+			t.Fatalf("got %v, %v, want error 408", file, err)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("got %d values, want the error only", n)
+	}
+}
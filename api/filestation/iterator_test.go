@@ -0,0 +1,124 @@
+package filestation
+
+import (
+	"errors"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/internal/fakedsm"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newTestListApi(t *testing.T, n int) (*Api, *fakedsm.Server, []string) {
+	files := map[string]string{
+		"/other/": "",
+	}
+	var names []string
+	for i := 0; i < n; i++ {
+		name := "f" + strconv.Itoa(100+i)
+		files["/share/dir/"+name] = name
+		names = append(names, name)
+	}
+	a, s := newTestApi(t, files)
+	return a, s, names
+}
+
+func collectNames(t *testing.T, it *Iterator[File]) string {
+	t.Helper()
+	var names []string
+	for it.Next() {
+		names = append(names, it.Value().Name)
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	return strings.Join(names, ",")
+}
+
+func TestListIter(t *testing.T) {
+	a, _, names := newTestListApi(t, 7)
+	tests := []struct {
+		offset int
+		limit  int
+	}{
+		{0, 0},
+		{0, 1},
+		{0, 3},
+		{0, 7},
+		{0, 100},
+		{2, 3},
+		{7, 3},
+	}
+	for _, test := range tests {
+		it := a.ListIter(ListRequest{
+			FolderPath: "/share/dir",
+			Offset:     test.offset,
+			Limit:      test.limit,
+			SortBy:     SortByName,
+		})
+		got := collectNames(t, it)
+		want := strings.Join(names[test.offset:], ",")
+		if got != want {
+			t.Errorf("offset %d, limit %d: got %s, want %s", test.offset, test.limit, got, want)
+		}
+		if it.Total() != len(names) {
+			t.Errorf("offset %d, limit %d: got total %d, want %d", test.offset, test.limit, it.Total(), len(names))
+		}
+		if it.Next() {
+			t.Errorf("offset %d, limit %d: Next after the end", test.offset, test.limit)
+		}
+	}
+}
+
+// TestListIterServerPageSize checks that pages shorter than requested, as
+// returned by servers capping the page size, do not end the iteration.
+func TestListIterServerPageSize(t *testing.T) {
+	a, s, names := newTestListApi(t, 7)
+	s.SetPageSize(2)
+	it := a.ListIter(ListRequest{
+		FolderPath: "/share/dir",
+		Limit:      5,
+		SortBy:     SortByName,
+	})
+	got := collectNames(t, it)
+	if got != strings.Join(names, ",") {
+		t.Fatalf("got %s", got)
+	}
+	if n := s.Calls("SYNO.FileStation.List.list"); n != 4 {
+		t.Fatalf("got %d list calls, want 4", n)
+	}
+}
+
+func TestListIterError(t *testing.T) {
+	a, _, _ := newTestListApi(t, 1)
+	it := a.ListIter(ListRequest{
+		FolderPath: "/share/missing",
+	})
+	if it.Next() {
+		t.Fatal("expected no files")
+	}
+	var apiError *api.Error
+	if !errors.As(it.Err(), &apiError) || apiError.Code != 408 {
+		t.Fatalf("got %v, want error 408", it.Err())
+	}
+	if it.Next() {
+		t.Fatal("Next after an error")
+	}
+}
+
+func TestListShareIter(t *testing.T) {
+	a, _, _ := newTestListApi(t, 1)
+	it := a.ListShareIter(ListShareRequest{
+		Limit: 1,
+	})
+	var names []string
+	for it.Next() {
+		names = append(names, it.Value().Name)
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if strings.Join(names, ",") != "other,share" {
+		t.Fatalf("got %v", names)
+	}
+}
@@ -0,0 +1,92 @@
+package filestation
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"github.com/ngyewch/go-syno/api"
+	"io"
+	"os"
+	"strings"
+)
+
+type MD5Request struct {
+	FilePath string
+}
+
+type MD5StartResponse struct {
+	TaskId string `json:"taskid"`
+}
+
+type MD5StatusResponse struct {
+	Finished bool   `json:"finished"`
+	MD5      string `json:"md5,omitempty"`
+}
+
+type MD5Task struct {
+	*backgroundTask[MD5StatusResponse]
+}
+
+func (a *Api) MD5(req MD5Request) (*MD5Task, error) {
+	paramMap := make(map[string]string)
+	paramMap["file_path"] = req.FilePath
+
+	var res api.Response[MD5StartResponse]
+	err := a.client.Request("SYNO.FileStation.MD5", 2, "start", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.Data == nil {
+		return nil, errNoTaskId
+	}
+	return &MD5Task{
+		backgroundTask: newBackgroundTask(a.client, "SYNO.FileStation.MD5", 2, res.Data.TaskId, func(status *MD5StatusResponse) TaskStatus {
+			return TaskStatus{
+				Finished: status.Finished,
+				Progress: -1,
+			}
+		}),
+	}, nil
+}
+
+// VerifyMD5 computes the MD5 digest of remotePath on the server and compares
+// it against the digest of r.
+func (a *Api) VerifyMD5(ctx context.Context, remotePath string, r io.Reader) (bool, error) {
+	task, err := a.MD5(MD5Request{
+		FilePath: remotePath,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	h := md5.New()
+	_, err = io.Copy(h, r)
+	if err != nil {
+		_ = task.Stop()
+		return false, err
+	}
+	localMD5 := hex.EncodeToString(h.Sum(nil))
+
+	status, err := task.Wait(ctx)
+	if err != nil {
+		_ = task.Stop()
+		return false, err
+	}
+
+	return strings.EqualFold(status.MD5, localMD5), nil
+}
+
+func (a *Api) VerifyMD5File(ctx context.Context, remotePath string, localPath string) (bool, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return false, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	return a.VerifyMD5(ctx, remotePath, f)
+}
@@ -0,0 +1,45 @@
+package filestation
+
+type AdditionalField string
+
+const (
+	AdditionalRealPath       AdditionalField = "real_path"
+	AdditionalSize           AdditionalField = "size"
+	AdditionalOwner          AdditionalField = "owner"
+	AdditionalTime           AdditionalField = "time"
+	AdditionalPerm           AdditionalField = "perm"
+	AdditionalMountPointType AdditionalField = "mount_point_type"
+	AdditionalType           AdditionalField = "type"
+	AdditionalVolumeStatus   AdditionalField = "volume_status"
+	AdditionalSyncShare      AdditionalField = "sync_share"
+)
+
+type SortBy string
+
+const (
+	SortByName   SortBy = "name"
+	SortBySize   SortBy = "size"
+	SortByUser   SortBy = "user"
+	SortByGroup  SortBy = "group"
+	SortByMtime  SortBy = "mtime"
+	SortByAtime  SortBy = "atime"
+	SortByCtime  SortBy = "ctime"
+	SortByCrtime SortBy = "crtime"
+	SortByPosix  SortBy = "posix"
+	SortByType   SortBy = "type"
+)
+
+type SortDirection string
+
+const (
+	SortAscending  SortDirection = "asc"
+	SortDescending SortDirection = "desc"
+)
+
+type FileType string
+
+const (
+	FileTypeFile FileType = "file"
+	FileTypeDir  FileType = "dir"
+	FileTypeAll  FileType = "all"
+)
@@ -0,0 +1,55 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+)
+
+type RenameRequest struct {
+	Path         []string
+	Name         []string
+	Additional   []AdditionalField
+	SearchTaskId string
+}
+
+type RenameResponse struct {
+	Files []File `json:"files,omitempty"`
+}
+
+func (a *Api) Rename(req RenameRequest) (*api.Response[RenameResponse], error) {
+	paramMap := make(map[string]string)
+	if len(req.Path) > 0 {
+		jsonBytes, err := json.Marshal(req.Path)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["path"] = string(jsonBytes)
+	}
+	if len(req.Name) > 0 {
+		jsonBytes, err := json.Marshal(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["name"] = string(jsonBytes)
+	}
+	if len(req.Additional) > 0 {
+		jsonBytes, err := json.Marshal(req.Additional)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["additional"] = string(jsonBytes)
+	}
+	if req.SearchTaskId != "" {
+		paramMap["search_taskid"] = req.SearchTaskId
+	}
+
+	var res api.Response[RenameResponse]
+	err := a.client.Request("SYNO.FileStation.Rename", 2, "rename", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
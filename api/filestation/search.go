@@ -0,0 +1,336 @@
+package filestation
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSearchPageSize = 500
+)
+
+type SearchStartRequest struct {
+	FolderPath []string
+	Recursive  bool
+	Pattern    string
+	Extension  []string
+	FileType   FileType
+	SizeFrom   int64
+	SizeTo     int64
+	MtimeFrom  time.Time
+	MtimeTo    time.Time
+	Owner      string
+	Group      string
+}
+
+type SearchStartResponse struct {
+	TaskId string `json:"taskid"`
+}
+
+type SearchListRequest struct {
+	TaskId        string
+	Offset        int
+	Limit         int
+	SortBy        SortBy
+	SortDirection SortDirection
+	Pattern       []string
+	FileType      FileType
+	Additional    []AdditionalField
+}
+
+type SearchListResponse struct {
+	Total    int    `json:"total"`
+	Offset   int    `json:"offset"`
+	Finished bool   `json:"finished"`
+	Files    []File `json:"files,omitempty"`
+}
+
+type SearchStopRequest struct {
+	TaskId []string
+}
+
+type SearchStopResponse struct{}
+
+type SearchCleanRequest struct {
+	TaskId []string
+}
+
+type SearchCleanResponse struct{}
+
+type SearchRequest struct {
+	SearchStartRequest
+	PageSize   int
+	Additional []AdditionalField
+}
+
+func (a *Api) SearchStart(req SearchStartRequest) (*api.Response[SearchStartResponse], error) {
+	paramMap := make(map[string]string)
+	if len(req.FolderPath) > 0 {
+		jsonBytes, err := json.Marshal(req.FolderPath)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["folder_path"] = string(jsonBytes)
+	}
+	paramMap["recursive"] = strconv.FormatBool(req.Recursive)
+	if req.Pattern != "" {
+		paramMap["pattern"] = req.Pattern
+	}
+	if len(req.Extension) > 0 {
+		paramMap["extension"] = strings.Join(req.Extension, ",")
+	}
+	if req.FileType != "" {
+		paramMap["filetype"] = string(req.FileType)
+	}
+	if req.SizeFrom != 0 {
+		paramMap["size_from"] = strconv.FormatInt(req.SizeFrom, 10)
+	}
+	if req.SizeTo != 0 {
+		paramMap["size_to"] = strconv.FormatInt(req.SizeTo, 10)
+	}
+	if !req.MtimeFrom.IsZero() {
+		paramMap["mtime_from"] = strconv.FormatInt(req.MtimeFrom.Unix(), 10)
+	}
+	if !req.MtimeTo.IsZero() {
+		paramMap["mtime_to"] = strconv.FormatInt(req.MtimeTo.Unix(), 10)
+	}
+	if req.Owner != "" {
+		paramMap["owner"] = req.Owner
+	}
+	if req.Group != "" {
+		paramMap["group"] = req.Group
+	}
+
+	var res api.Response[SearchStartResponse]
+	err := a.client.Request("SYNO.FileStation.Search", 2, "start", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) SearchList(req SearchListRequest) (*api.Response[SearchListResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["taskid"] = req.TaskId
+	paramMap["offset"] = strconv.Itoa(req.Offset)
+	if req.Limit != 0 {
+		paramMap["limit"] = strconv.Itoa(req.Limit)
+	}
+	if req.SortBy != "" {
+		paramMap["sort_by"] = string(req.SortBy)
+	}
+	if req.SortDirection != "" {
+		paramMap["sort_direction"] = string(req.SortDirection)
+	}
+	if len(req.Pattern) > 0 {
+		paramMap["pattern"] = strings.Join(req.Pattern, ",")
+	}
+	if req.FileType != "" {
+		paramMap["filetype"] = string(req.FileType)
+	}
+	if len(req.Additional) > 0 {
+		jsonBytes, err := json.Marshal(req.Additional)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["additional"] = string(jsonBytes)
+	}
+
+	var res api.Response[SearchListResponse]
+	err := a.client.Request("SYNO.FileStation.Search", 2, "list", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) SearchStop(req SearchStopRequest) (*api.Response[SearchStopResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["taskid"] = strings.Join(req.TaskId, ",")
+
+	var res api.Response[SearchStopResponse]
+	err := a.client.Request("SYNO.FileStation.Search", 2, "stop", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) SearchClean(req SearchCleanRequest) (*api.Response[SearchCleanResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["taskid"] = strings.Join(req.TaskId, ",")
+
+	var res api.Response[SearchCleanResponse]
+	err := a.client.Request("SYNO.FileStation.Search", 2, "clean", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+// Search starts a search task and returns an iterator over its results.
+// Results are yielded as the server finds them; the caller must Close the
+// iterator to stop and clean up the search task.
+func (a *Api) Search(ctx context.Context, req SearchRequest) (*SearchIterator, error) {
+	startResponse, err := a.SearchStart(req.SearchStartRequest)
+	if err != nil {
+		return nil, err
+	}
+	if startResponse.Data == nil {
+		return nil, errNoTaskId
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultSearchPageSize
+	}
+	return &SearchIterator{
+		api:        a,
+		ctx:        ctx,
+		taskId:     startResponse.Data.TaskId,
+		pageSize:   pageSize,
+		additional: req.Additional,
+	}, nil
+}
+
+type SearchIterator struct {
+	api        *Api
+	ctx        context.Context
+	taskId     string
+	pageSize   int
+	additional []AdditionalField
+
+	offset   int
+	finished bool
+	files    []File
+	file     File
+	err      error
+	closed   bool
+}
+
+func (it *SearchIterator) Api() string {
+	return "SYNO.FileStation.Search"
+}
+
+func (it *SearchIterator) TaskId() string {
+	return it.taskId
+}
+
+func (it *SearchIterator) Poll() (*TaskStatus, error) {
+	listResponse, err := it.api.SearchList(SearchListRequest{
+		TaskId: it.taskId,
+		Offset: it.offset,
+		Limit:  1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if listResponse.Data == nil {
+		return &TaskStatus{
+			Progress: -1,
+		}, nil
+	}
+	return &TaskStatus{
+		Finished: listResponse.Data.Finished,
+		Progress: -1,
+	}, nil
+}
+
+func (it *SearchIterator) Stop() error {
+	_, err := it.api.SearchStop(SearchStopRequest{
+		TaskId: []string{it.taskId},
+	})
+	return err
+}
+
+func (it *SearchIterator) Next() bool {
+	if it.err != nil || it.closed {
+		return false
+	}
+	for len(it.files) == 0 {
+		if it.finished {
+			return false
+		}
+		if !it.fetch() {
+			return false
+		}
+	}
+	it.file = it.files[0]
+	it.files = it.files[1:]
+	return true
+}
+
+func (it *SearchIterator) fetch() bool {
+	for {
+		listResponse, err := it.api.SearchList(SearchListRequest{
+			TaskId:     it.taskId,
+			Offset:     it.offset,
+			Limit:      it.pageSize,
+			Additional: it.additional,
+		})
+		if err != nil {
+			it.err = err
+			return false
+		}
+		data := listResponse.Data
+		if data == nil {
+			it.err = errNoData
+			return false
+		}
+		if len(data.Files) > 0 {
+			it.files = data.Files
+			it.offset += len(data.Files)
+			it.finished = data.Finished && it.offset >= data.Total
+			return true
+		}
+		if data.Finished {
+			it.finished = true
+			return true
+		}
+
+		select {
+		case <-it.ctx.Done():
+			it.err = it.ctx.Err()
+			return false
+		case <-time.After(defaultPollInterval):
+		}
+	}
+}
+
+func (it *SearchIterator) File() File {
+	return it.file
+}
+
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
+func (it *SearchIterator) Close() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+	stopErr := it.Stop()
+	_, cleanErr := it.api.SearchClean(SearchCleanRequest{
+		TaskId: []string{it.taskId},
+	})
+	if stopErr != nil {
+		return stopErr
+	}
+	return cleanErr
+}
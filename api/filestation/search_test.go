@@ -0,0 +1,155 @@
+package filestation
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSearchFiles = map[string]string{
+	"/share/readme.md":         "",
+	"/share/notes.txt":         "",
+	"/share/docs/guide.md":     "",
+	"/share/docs/README.txt":   "",
+	"/share/docs/old/read.md":  "",
+	"/share/src/main.go":       "",
+	"/share/src/reader.go":     "",
+	"/other/readme.md":         "",
+	"/share/empty/":            "",
+	"/share/docs/old/draft.md": "",
+}
+
+func search(t *testing.T, a *Api, req SearchRequest) []string {
+	t.Helper()
+	it, err := a.Search(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for it.Next() {
+		paths = append(paths, it.File().Path)
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	err = it.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestSearch(t *testing.T) {
+	a, s := newTestApi(t, testSearchFiles)
+	tests := []struct {
+		req  SearchStartRequest
+		want string
+	}{
+		{
+			SearchStartRequest{FolderPath: []string{"/share"}, Recursive: true, Pattern: "read"},
+			"/share/docs/README.txt,/share/docs/old/read.md,/share/readme.md,/share/src/reader.go",
+		},
+		{
+			SearchStartRequest{FolderPath: []string{"/share"}, Pattern: "read"},
+			"/share/readme.md",
+		},
+		{
+			SearchStartRequest{FolderPath: []string{"/share"}, Recursive: true, Extension: []string{"md"}},
+			"/share/docs/guide.md,/share/docs/old/draft.md,/share/docs/old/read.md,/share/readme.md",
+		},
+		{
+			SearchStartRequest{FolderPath: []string{"/share/src", "/other"}, Recursive: true, Pattern: "read"},
+			"/other/readme.md,/share/src/reader.go",
+		},
+		{
+			SearchStartRequest{FolderPath: []string{"/share"}, Recursive: true, Pattern: "missing"},
+			"",
+		},
+	}
+	for _, pageSize := range []int{0, 1, 3} {
+		for _, test := range tests {
+			got := search(t, a, SearchRequest{
+				SearchStartRequest: test.req,
+				PageSize:           pageSize,
+			})
+			if strings.Join(got, ",") != test.want {
+				t.Errorf("%+v, page size %d: got %v, want %s", test.req, pageSize, got, test.want)
+			}
+		}
+	}
+	if searches := s.Searches(); len(searches) != 0 {
+		t.Fatalf("search tasks %v not cleaned", searches)
+	}
+}
+
+// TestSearchInProgress checks that results found while the search is still
+// running are all returned.
+func TestSearchInProgress(t *testing.T) {
+	a, s := newTestApi(t, testSearchFiles)
+	s.SetSearchStep(1)
+	got := search(t, a, SearchRequest{
+		SearchStartRequest: SearchStartRequest{
+			FolderPath: []string{"/share"},
+			Recursive:  true,
+			Extension:  []string{"md"},
+		},
+		PageSize: 2,
+	})
+	want := "/share/docs/guide.md,/share/docs/old/draft.md,/share/docs/old/read.md,/share/readme.md"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("got %v, want %s", got, want)
+	}
+}
+
+func TestSearchCancel(t *testing.T) {
+	a, s := newTestApi(t, testSearchFiles)
+	s.SetSearchStep(-1)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	it, err := a.Search(ctx, SearchRequest{
+		SearchStartRequest: SearchStartRequest{
+			FolderPath: []string{"/share"},
+			Recursive:  true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := it.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Finished {
+		t.Fatal("expected the search to be running")
+	}
+	if it.Next() {
+		t.Fatal("expected no results")
+	}
+	if !errors.Is(it.Err(), context.DeadlineExceeded) {
+		t.Fatalf("got %v, want %v", it.Err(), context.DeadlineExceeded)
+	}
+	err = it.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if searches := s.Searches(); len(searches) != 0 {
+		t.Fatalf("search tasks %v not cleaned", searches)
+	}
+}
+
+func TestSearchError(t *testing.T) {
+	a, _ := newTestApi(t, testSearchFiles)
+	_, err := a.Search(context.Background(), SearchRequest{
+		SearchStartRequest: SearchStartRequest{
+			FolderPath: []string{"/missing"},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,227 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sharingDateLayout = "2006-01-02"
+)
+
+type SharingLink struct {
+	Id            string `json:"id,omitempty"`
+	Url           string `json:"url,omitempty"`
+	LinkOwner     string `json:"link_owner,omitempty"`
+	Path          string `json:"path,omitempty"`
+	IsFolder      bool   `json:"isFolder"`
+	HasPassword   bool   `json:"has_password"`
+	DateExpired   string `json:"date_expired,omitempty"`
+	DateAvailable string `json:"date_available,omitempty"`
+	Status        string `json:"status,omitempty"`
+	QrCode        string `json:"qrcode,omitempty"`
+}
+
+type SharingGetInfoRequest struct {
+	Id string
+}
+
+type SharingGetInfoResponse SharingLink
+
+type SharingSortBy string
+
+const (
+	SharingSortById            SharingSortBy = "id"
+	SharingSortByName          SharingSortBy = "name"
+	SharingSortByIsFolder      SharingSortBy = "isFolder"
+	SharingSortByPath          SharingSortBy = "path"
+	SharingSortByDateExpired   SharingSortBy = "date_expired"
+	SharingSortByDateAvailable SharingSortBy = "date_available"
+	SharingSortByStatus        SharingSortBy = "status"
+	SharingSortByHasPassword   SharingSortBy = "has_password"
+	SharingSortByUrl           SharingSortBy = "url"
+	SharingSortByLinkOwner     SharingSortBy = "link_owner"
+)
+
+type SharingListRequest struct {
+	Offset        int
+	Limit         int
+	SortBy        SharingSortBy
+	SortDirection SortDirection
+	ForceClean    bool
+}
+
+type SharingListResponse struct {
+	Total  int           `json:"total"`
+	Offset int           `json:"offset"`
+	Links  []SharingLink `json:"links,omitempty"`
+}
+
+type SharingCreateRequest struct {
+	Path          []string
+	Password      string
+	DateExpired   time.Time
+	DateAvailable time.Time
+}
+
+type SharingCreateResponse struct {
+	Links []SharingLink `json:"links,omitempty"`
+}
+
+type SharingEditRequest struct {
+	Id            []string
+	Password      string
+	DateExpired   time.Time
+	DateAvailable time.Time
+	// ClearPassword removes the password, ignoring Password.
+	ClearPassword bool
+	// ClearDateExpired makes the link permanent, ignoring DateExpired.
+	ClearDateExpired bool
+	// ClearDateAvailable makes the link available immediately, ignoring
+	// DateAvailable.
+	ClearDateAvailable bool
+}
+
+type SharingEditResponse struct{}
+
+type SharingDeleteRequest struct {
+	Id []string
+}
+
+type SharingDeleteResponse struct{}
+
+type SharingClearInvalidResponse struct{}
+
+func (a *Api) SharingGetInfo(req SharingGetInfoRequest) (*api.Response[SharingGetInfoResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["id"] = req.Id
+
+	var res api.Response[SharingGetInfoResponse]
+	err := a.client.Request("SYNO.FileStation.Sharing", 3, "getinfo", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) SharingList(req SharingListRequest) (*api.Response[SharingListResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["offset"] = strconv.Itoa(req.Offset)
+	if req.Limit != 0 {
+		paramMap["limit"] = strconv.Itoa(req.Limit)
+	}
+	if req.SortBy != "" {
+		paramMap["sort_by"] = string(req.SortBy)
+	}
+	if req.SortDirection != "" {
+		paramMap["sort_direction"] = string(req.SortDirection)
+	}
+	if req.ForceClean {
+		paramMap["force_clean"] = strconv.FormatBool(req.ForceClean)
+	}
+
+	var res api.Response[SharingListResponse]
+	err := a.client.Request("SYNO.FileStation.Sharing", 3, "list", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) SharingCreate(req SharingCreateRequest) (*api.Response[SharingCreateResponse], error) {
+	paramMap := make(map[string]string)
+	if len(req.Path) > 0 {
+		jsonBytes, err := json.Marshal(req.Path)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["path"] = string(jsonBytes)
+	}
+	if req.Password != "" {
+		paramMap["password"] = req.Password
+	}
+	if !req.DateExpired.IsZero() {
+		paramMap["date_expired"] = req.DateExpired.Format(sharingDateLayout)
+	}
+	if !req.DateAvailable.IsZero() {
+		paramMap["date_available"] = req.DateAvailable.Format(sharingDateLayout)
+	}
+
+	var res api.Response[SharingCreateResponse]
+	err := a.client.Request("SYNO.FileStation.Sharing", 3, "create", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) SharingEdit(req SharingEditRequest) (*api.Response[SharingEditResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["id"] = strings.Join(req.Id, ",")
+	if req.ClearPassword {
+		paramMap["password"] = ""
+	} else if req.Password != "" {
+		paramMap["password"] = req.Password
+	}
+	if req.ClearDateExpired {
+		paramMap["date_expired"] = "0"
+	} else if !req.DateExpired.IsZero() {
+		paramMap["date_expired"] = req.DateExpired.Format(sharingDateLayout)
+	}
+	if req.ClearDateAvailable {
+		paramMap["date_available"] = "0"
+	} else if !req.DateAvailable.IsZero() {
+		paramMap["date_available"] = req.DateAvailable.Format(sharingDateLayout)
+	}
+
+	var res api.Response[SharingEditResponse]
+	err := a.client.Request("SYNO.FileStation.Sharing", 3, "edit", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) SharingDelete(req SharingDeleteRequest) (*api.Response[SharingDeleteResponse], error) {
+	paramMap := make(map[string]string)
+	paramMap["id"] = strings.Join(req.Id, ",")
+
+	var res api.Response[SharingDeleteResponse]
+	err := a.client.Request("SYNO.FileStation.Sharing", 3, "delete", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (a *Api) SharingClearInvalid() (*api.Response[SharingClearInvalidResponse], error) {
+	paramMap := make(map[string]string)
+
+	var res api.Response[SharingClearInvalidResponse]
+	err := a.client.Request("SYNO.FileStation.Sharing", 3, "clear_invalid", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
@@ -0,0 +1,158 @@
+package filestation
+
+import (
+	"context"
+	"errors"
+	"github.com/ngyewch/go-syno/api"
+	"time"
+)
+
+const (
+	defaultPollInterval = time.Second
+)
+
+var (
+	errNoTaskId = errors.New("response does not contain a task id")
+	errNoData   = errors.New("response does not contain data")
+)
+
+var (
+	_ Task = (*CopyMoveTask)(nil)
+	_ Task = (*DeleteTask)(nil)
+	_ Task = (*DirSizeTask)(nil)
+	_ Task = (*MD5Task)(nil)
+	_ Task = (*CompressTask)(nil)
+	_ Task = (*ExtractTask)(nil)
+	_ Task = (*SearchIterator)(nil)
+)
+
+// Task is implemented by the handles returned from every long-running
+// FileStation operation.
+type Task interface {
+	Api() string
+	TaskId() string
+	Poll() (*TaskStatus, error)
+	Stop() error
+}
+
+type TaskStatus struct {
+	Finished bool
+	// Progress is in the range [0, 1], or -1 if the API does not report it.
+	Progress float64
+}
+
+// WaitTask polls t until it is finished or ctx is done.
+func WaitTask(ctx context.Context, t Task) (*TaskStatus, error) {
+	ticker := time.NewTicker(defaultPollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := t.Poll()
+		if err != nil {
+			return nil, err
+		}
+		if status.Finished {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+type backgroundTask[T any] struct {
+	client     *api.Client
+	api        string
+	version    int
+	taskId     string
+	taskStatus func(status *T) TaskStatus
+}
+
+type taskStopResponse struct{}
+
+func newBackgroundTask[T any](client *api.Client, api string, version int, taskId string, taskStatus func(status *T) TaskStatus) *backgroundTask[T] {
+	return &backgroundTask[T]{
+		client:     client,
+		api:        api,
+		version:    version,
+		taskId:     taskId,
+		taskStatus: taskStatus,
+	}
+}
+
+func (t *backgroundTask[T]) Api() string {
+	return t.api
+}
+
+func (t *backgroundTask[T]) TaskId() string {
+	return t.taskId
+}
+
+func (t *backgroundTask[T]) Status() (*api.Response[T], error) {
+	paramMap := make(map[string]string)
+	paramMap["taskid"] = t.taskId
+
+	var res api.Response[T]
+	err := t.client.Request(t.api, t.version, "status", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+func (t *backgroundTask[T]) Poll() (*TaskStatus, error) {
+	res, err := t.Status()
+	if err != nil {
+		return nil, err
+	}
+	if res.Data == nil {
+		return &TaskStatus{
+			Progress: -1,
+		}, nil
+	}
+	taskStatus := t.taskStatus(res.Data)
+	return &taskStatus, nil
+}
+
+func (t *backgroundTask[T]) Stop() error {
+	paramMap := make(map[string]string)
+	paramMap["taskid"] = t.taskId
+
+	var res api.Response[taskStopResponse]
+	err := t.client.Request(t.api, t.version, "stop", paramMap, &res)
+	if err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+	return nil
+}
+
+// Wait polls the task status until it is finished or ctx is done.
+func (t *backgroundTask[T]) Wait(ctx context.Context) (*T, error) {
+	ticker := time.NewTicker(defaultPollInterval)
+	defer ticker.Stop()
+
+	for {
+		res, err := t.Status()
+		if err != nil {
+			return nil, err
+		}
+		if res.Data != nil && t.taskStatus(res.Data).Finished {
+			return res.Data, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package filestation
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+)
+
+type ThumbSize string
+
+const (
+	ThumbSizeSmall    ThumbSize = "small"
+	ThumbSizeMedium   ThumbSize = "medium"
+	ThumbSizeLarge    ThumbSize = "large"
+	ThumbSizeOriginal ThumbSize = "original"
+)
+
+type ThumbRequest struct {
+	Path   string
+	Size   ThumbSize
+	Rotate int
+}
+
+func (a *Api) Thumb(req ThumbRequest) (*api.RawResponse, error) {
+	paramMap := make(map[string]string)
+	paramMap["path"] = req.Path
+	if req.Size != "" {
+		paramMap["size"] = string(req.Size)
+	}
+	if req.Rotate != 0 {
+		paramMap["rotate"] = strconv.Itoa(req.Rotate)
+	}
+	return a.client.RawRequest("SYNO.FileStation.Thumb", 2, "get", paramMap)
+}
@@ -0,0 +1,114 @@
+package filestation
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"io"
+	"strconv"
+	"time"
+)
+
+type OverwriteMode int
+
+const (
+	// OverwriteError fails the operation if the destination already exists.
+	OverwriteError OverwriteMode = iota
+	OverwriteOverwrite
+	OverwriteSkip
+)
+
+type UploadRequest struct {
+	Path          string
+	FileName      string
+	Reader        io.Reader
+	CreateParents bool
+	Overwrite     OverwriteMode
+	Mtime         time.Time
+	Crtime        time.Time
+	Atime         time.Time
+	Progress      func(bytesUploaded int64)
+	// CheckPermission performs a CheckPermission pre-flight before any bytes
+	// are transferred.
+	CheckPermission bool
+}
+
+type UploadResponse struct {
+	Skipped  bool   `json:"blSkip"`
+	File     string `json:"file,omitempty"`
+	Pid      int    `json:"pid,omitempty"`
+	Progress int    `json:"progress,omitempty"`
+}
+
+func (a *Api) Upload(req UploadRequest) (*api.Response[UploadResponse], error) {
+	if req.CheckPermission {
+		_, err := a.CheckPermission(CheckPermissionRequest{
+			Path:       req.Path,
+			Filename:   req.FileName,
+			Overwrite:  req.Overwrite == OverwriteOverwrite,
+			CreateOnly: req.Overwrite != OverwriteOverwrite,
+		})
+		if err != nil {
+			if req.Overwrite == OverwriteSkip && isAlreadyExists(err) {
+				return &api.Response[UploadResponse]{
+					Success: true,
+					Data: &UploadResponse{
+						Skipped: true,
+						File:    req.FileName,
+					},
+				}, nil
+			}
+			return nil, err
+		}
+	}
+
+	paramMap := make(map[string]string)
+	paramMap["path"] = req.Path
+	paramMap["create_parents"] = strconv.FormatBool(req.CreateParents)
+	switch req.Overwrite {
+	case OverwriteOverwrite:
+		paramMap["overwrite"] = "true"
+	case OverwriteSkip:
+		paramMap["overwrite"] = "false"
+	}
+	if !req.Mtime.IsZero() {
+		paramMap["mtime"] = strconv.FormatInt(req.Mtime.UnixMilli(), 10)
+	}
+	if !req.Crtime.IsZero() {
+		paramMap["crtime"] = strconv.FormatInt(req.Crtime.UnixMilli(), 10)
+	}
+	if !req.Atime.IsZero() {
+		paramMap["atime"] = strconv.FormatInt(req.Atime.UnixMilli(), 10)
+	}
+
+	var r io.Reader = req.Reader
+	if req.Progress != nil {
+		r = &progressReader{
+			r:        r,
+			progress: req.Progress,
+		}
+	}
+
+	var res api.Response[UploadResponse]
+	err := a.client.MultipartRequest("SYNO.FileStation.Upload", 2, "upload", paramMap, "file", req.FileName, r, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
+
+type progressReader struct {
+	r        io.Reader
+	n        int64
+	progress func(bytesUploaded int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.n += int64(n)
+		p.progress(p.n)
+	}
+	return n, err
+}
@@ -0,0 +1,79 @@
+package filestation
+
+import (
+	"encoding/json"
+	"github.com/ngyewch/go-syno/api"
+	"strconv"
+)
+
+type VirtualFolder struct {
+	Path       string                   `json:"path,omitempty"`
+	Name       string                   `json:"name,omitempty"`
+	Additional *VirtualFolderAdditional `json:"additional,omitempty"`
+}
+
+type VirtualFolderAdditional struct {
+	RealPath       string        `json:"real_path,omitempty"`
+	Owner          *Owner        `json:"owner,omitempty"`
+	Time           *Time         `json:"time,omitempty"`
+	Perm           *Permissions  `json:"perm,omitempty"`
+	MountPointType string        `json:"mount_point_type,omitempty"`
+	VolumeStatus   *VolumeStatus `json:"volume_status,omitempty"`
+}
+
+type VirtualFolderType string
+
+const (
+	VirtualFolderTypeCifs VirtualFolderType = "cifs"
+	VirtualFolderTypeNfs  VirtualFolderType = "nfs"
+	VirtualFolderTypeIso  VirtualFolderType = "iso"
+)
+
+type VirtualFolderListRequest struct {
+	Type          VirtualFolderType
+	Offset        int
+	Limit         int
+	SortBy        SortBy
+	SortDirection SortDirection
+	Additional    []AdditionalField
+}
+
+type VirtualFolderListResponse struct {
+	Total   int             `json:"total"`
+	Offset  int             `json:"offset"`
+	Folders []VirtualFolder `json:"folders,omitempty"`
+}
+
+func (a *Api) VirtualFolderList(req VirtualFolderListRequest) (*api.Response[VirtualFolderListResponse], error) {
+	paramMap := make(map[string]string)
+	if req.Type != "" {
+		paramMap["type"] = string(req.Type)
+	}
+	paramMap["offset"] = strconv.Itoa(req.Offset)
+	if req.Limit != 0 {
+		paramMap["limit"] = strconv.Itoa(req.Limit)
+	}
+	if req.SortBy != "" {
+		paramMap["sort_by"] = string(req.SortBy)
+	}
+	if req.SortDirection != "" {
+		paramMap["sort_direction"] = string(req.SortDirection)
+	}
+	if len(req.Additional) > 0 {
+		jsonBytes, err := json.Marshal(req.Additional)
+		if err != nil {
+			return nil, err
+		}
+		paramMap["additional"] = string(jsonBytes)
+	}
+
+	var res api.Response[VirtualFolderListResponse]
+	err := a.client.Request("SYNO.FileStation.VirtualFolder", 2, "list", paramMap, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return &res, nil
+}
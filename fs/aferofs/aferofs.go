@@ -0,0 +1,288 @@
+package aferofs
+
+import (
+	"errors"
+	synofs "github.com/ngyewch/go-syno/fs"
+	"github.com/spf13/afero"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	_ afero.Fs   = (*Fs)(nil)
+	_ afero.File = (*File)(nil)
+)
+
+var (
+	errNotSupported = errors.New("operation not supported")
+)
+
+// Fs adapts a synofs.WritableFS to afero.Fs. Paths may be absolute or
+// relative; both are resolved against the root of the WritableFS.
+type Fs struct {
+	fs *synofs.WritableFS
+}
+
+func New(fs *synofs.WritableFS) *Fs {
+	return &Fs{
+		fs: fs,
+	}
+}
+
+func (a *Fs) Name() string {
+	return "SynoFs"
+}
+
+func normalize(name string) string {
+	name = path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	if name == "/" {
+		return "."
+	}
+	return name[1:]
+}
+
+func (a *Fs) Create(name string) (afero.File, error) {
+	w, err := a.fs.Create(normalize(name))
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		name: name,
+		w:    w,
+	}, nil
+}
+
+func (a *Fs) Mkdir(name string, perm os.FileMode) error {
+	return a.fs.Mkdir(normalize(name), perm)
+}
+
+func (a *Fs) MkdirAll(path string, perm os.FileMode) error {
+	return a.fs.MkdirAll(normalize(path), perm)
+}
+
+func (a *Fs) Open(name string) (afero.File, error) {
+	f, err := a.fs.Open(normalize(name))
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		name: name,
+		r:    f,
+	}, nil
+}
+
+// OpenFile supports read-only opens and write-only opens that create or
+// truncate the file, since FileStation can only replace whole files.
+func (a *Fs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	switch flag & (os.O_RDONLY | os.O_WRONLY | os.O_RDWR) {
+	case os.O_RDONLY:
+		if flag&(os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: errNotSupported}
+		}
+		return a.Open(name)
+	case os.O_WRONLY:
+		if flag&os.O_APPEND != 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: errNotSupported}
+		}
+		info, err := a.fs.Stat(normalize(name))
+		if err == nil {
+			if flag&(os.O_CREATE|os.O_EXCL) == os.O_CREATE|os.O_EXCL {
+				return nil, &os.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+			}
+			if isDir(info) {
+				return nil, &os.PathError{Op: "open", Path: name, Err: errors.New("is a directory")}
+			}
+			if flag&os.O_TRUNC == 0 {
+				return nil, &os.PathError{Op: "open", Path: name, Err: errNotSupported}
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) || flag&os.O_CREATE == 0 {
+			return nil, err
+		}
+		return a.Create(name)
+	default:
+		return nil, &os.PathError{Op: "open", Path: name, Err: errNotSupported}
+	}
+}
+
+func (a *Fs) Remove(name string) error {
+	return a.fs.Remove(normalize(name))
+}
+
+func (a *Fs) RemoveAll(path string) error {
+	return a.fs.RemoveAll(normalize(path))
+}
+
+func (a *Fs) Rename(oldname string, newname string) error {
+	return a.fs.Rename(normalize(oldname), normalize(newname))
+}
+
+func (a *Fs) Stat(name string) (os.FileInfo, error) {
+	return a.fs.Stat(normalize(name))
+}
+
+func (a *Fs) Chmod(name string, mode os.FileMode) error {
+	return &os.PathError{Op: "chmod", Path: name, Err: errNotSupported}
+}
+
+func (a *Fs) Chown(name string, uid int, gid int) error {
+	return &os.PathError{Op: "chown", Path: name, Err: errNotSupported}
+}
+
+func (a *Fs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return a.fs.Chtimes(normalize(name), atime, mtime)
+}
+
+// ----
+
+// File is either a read handle returned by Open or a write handle returned
+// by Create. If any write operation fails, Close aborts the upload so that a
+// partial file is never saved.
+type File struct {
+	name string
+	r    fs.File
+	w    *synofs.WritableFile
+	n    int64
+	err  error
+}
+
+func (f *File) Name() string {
+	return f.name
+}
+
+func (f *File) Close() error {
+	if f.w != nil {
+		if f.err != nil {
+			_ = f.w.CloseWithError(f.err)
+			return f.err
+		}
+		return f.w.Close()
+	}
+	return f.r.Close()
+}
+
+// fail records the first error of a write handle.
+func (f *File) fail(err error) error {
+	if f.w != nil && f.err == nil {
+		f.err = err
+	}
+	return err
+}
+
+func (f *File) Read(p []byte) (int, error) {
+	if f.w != nil {
+		return f.w.Read(p)
+	}
+	return f.r.Read(p)
+}
+
+func (f *File) ReadAt(p []byte, off int64) (int, error) {
+	readerAt, ok := f.r.(io.ReaderAt)
+	if !ok {
+		return 0, &os.PathError{Op: "readat", Path: f.name, Err: errNotSupported}
+	}
+	return readerAt.ReadAt(p, off)
+}
+
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.w != nil {
+		// only no-op seeks are possible on a streaming upload
+		if (whence == io.SeekCurrent && offset == 0) || (whence == io.SeekStart && offset == f.n) {
+			return f.n, nil
+		}
+		return 0, f.fail(&os.PathError{Op: "seek", Path: f.name, Err: errNotSupported})
+	}
+	seeker, ok := f.r.(io.Seeker)
+	if !ok {
+		return 0, &os.PathError{Op: "seek", Path: f.name, Err: errNotSupported}
+	}
+	return seeker.Seek(offset, whence)
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	if f.w == nil {
+		return 0, &os.PathError{Op: "write", Path: f.name, Err: errNotSupported}
+	}
+	if f.err != nil {
+		return 0, f.err
+	}
+	n, err := f.w.Write(p)
+	f.n += int64(n)
+	if err != nil {
+		return n, f.fail(err)
+	}
+	return n, nil
+}
+
+// WriteAt only supports writing at the current end of the upload.
+func (f *File) WriteAt(p []byte, off int64) (int, error) {
+	if f.w == nil || off != f.n {
+		return 0, f.fail(&os.PathError{Op: "writeat", Path: f.name, Err: errNotSupported})
+	}
+	return f.Write(p)
+}
+
+func (f *File) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *File) Readdir(count int) ([]os.FileInfo, error) {
+	dirEntries, err := f.readDir(count)
+	fileInfos := make([]os.FileInfo, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		fileInfo, err := dirEntry.Info()
+		if err != nil {
+			return fileInfos, err
+		}
+		fileInfos = append(fileInfos, fileInfo)
+	}
+	return fileInfos, err
+}
+
+func (f *File) Readdirnames(n int) ([]string, error) {
+	dirEntries, err := f.readDir(n)
+	names := make([]string, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		names = append(names, dirEntry.Name())
+	}
+	return names, err
+}
+
+func (f *File) readDir(n int) ([]fs.DirEntry, error) {
+	readDirFile, ok := f.r.(fs.ReadDirFile)
+	if !ok {
+		return nil, &os.PathError{Op: "readdir", Path: f.name, Err: errors.New("not a directory")}
+	}
+	return readDirFile.ReadDir(n)
+}
+
+func (f *File) Stat() (os.FileInfo, error) {
+	if f.w != nil {
+		return f.w.Stat()
+	}
+	return f.r.Stat()
+}
+
+func (f *File) Sync() error {
+	return nil
+}
+
+func (f *File) Truncate(size int64) error {
+	if f.w != nil && size == f.n {
+		return nil
+	}
+	return f.fail(&os.PathError{Op: "truncate", Path: f.name, Err: errNotSupported})
+}
+
+// isDir reports whether info describes a directory, including symlinks to
+// directories.
+func isDir(info os.FileInfo) bool {
+	sys, ok := info.Sys().(*synofs.Sys)
+	if ok {
+		return sys.File.IsDir
+	}
+	return info.IsDir()
+}
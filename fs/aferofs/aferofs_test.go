@@ -0,0 +1,267 @@
+package aferofs
+
+import (
+	"errors"
+	"github.com/ngyewch/go-syno/api"
+	synofs "github.com/ngyewch/go-syno/fs"
+	"github.com/ngyewch/go-syno/internal/fakedsm"
+	"github.com/spf13/afero"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestFs(t *testing.T) (*Fs, *fakedsm.Server) {
+	s := fakedsm.New(map[string]string{
+		"/share/hello.txt":    "hello, world\n",
+		"/share/dir/a.go":     "package a\n",
+		"/share/dir/sub/c.md": "# c\n",
+		"/share/empty/":       "",
+	})
+	t.Cleanup(s.Close)
+	client, err := api.NewClient(s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := synofs.NewWritableFS(client, "/share")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(f), s
+}
+
+func TestReadWriteFile(t *testing.T) {
+	a, s := newTestFs(t)
+
+	err := afero.WriteFile(a, "/dir/new.txt", []byte("new"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, ok := s.ReadFile("/share/dir/new.txt")
+	if !ok || content != "new" {
+		t.Fatalf("got %q", content)
+	}
+
+	data, err := afero.ReadFile(a, "dir/new.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new" {
+		t.Fatalf("got %q", data)
+	}
+
+	err = afero.WriteFile(a, "hello.txt", []byte("replaced"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = afero.ReadFile(a, `\hello.txt`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "replaced" {
+		t.Fatalf("got %q", data)
+	}
+
+	_, err = afero.ReadFile(a, "missing.txt")
+	if !os.IsNotExist(err) {
+		t.Fatalf("got %v, want not exist", err)
+	}
+}
+
+func TestExists(t *testing.T) {
+	a, _ := newTestFs(t)
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"hello.txt", true},
+		{"/dir/sub", true},
+		{"/", true},
+		{"missing", false},
+		{"dir/missing.txt", false},
+	}
+	for _, test := range tests {
+		got, err := afero.Exists(a, test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("Exists(%q): got %v, want %v", test.name, got, test.want)
+		}
+	}
+	isDir, err := afero.IsDir(a, "dir")
+	if err != nil || !isDir {
+		t.Errorf("IsDir(dir): got %v, %v", isDir, err)
+	}
+	isEmpty, err := afero.IsEmpty(a, "empty")
+	if err != nil || !isEmpty {
+		t.Errorf("IsEmpty(empty): got %v, %v", isEmpty, err)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	a, _ := newTestFs(t)
+	var paths []string
+	err := afero.Walk(a, "/", func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(p))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/", "/dir", "/dir/a.go", "/dir/sub", "/dir/sub/c.md", "/empty", "/hello.txt"}
+	sort.Strings(paths)
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Fatalf("got %v, want %v", paths, want)
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	a, s := newTestFs(t)
+
+	tests := []struct {
+		name string
+		flag int
+		want error
+	}{
+		{"hello.txt", os.O_RDONLY, nil},
+		{"missing.txt", os.O_RDONLY, fs.ErrNotExist},
+		{"hello.txt", os.O_RDONLY | os.O_CREATE, errNotSupported},
+		{"hello.txt", os.O_RDWR, errNotSupported},
+		{"hello.txt", os.O_WRONLY | os.O_APPEND, errNotSupported},
+		{"hello.txt", os.O_WRONLY, errNotSupported},
+		{"hello.txt", os.O_WRONLY | os.O_TRUNC, nil},
+		{"hello.txt", os.O_WRONLY | os.O_CREATE | os.O_EXCL, fs.ErrExist},
+		{"missing.txt", os.O_WRONLY | os.O_TRUNC, fs.ErrNotExist},
+		{"created.txt", os.O_WRONLY | os.O_CREATE | os.O_EXCL, nil},
+		{"dir", os.O_WRONLY | os.O_CREATE | os.O_TRUNC, nil},
+	}
+	for _, test := range tests {
+		f, err := a.OpenFile(test.name, test.flag, 0644)
+		if test.name == "dir" && test.flag&os.O_WRONLY != 0 {
+			if err == nil {
+				t.Errorf("OpenFile(%q, %#x): expected error", test.name, test.flag)
+				_ = f.Close()
+			}
+			continue
+		}
+		if test.want == nil {
+			if err != nil {
+				t.Errorf("OpenFile(%q, %#x): %v", test.name, test.flag, err)
+				continue
+			}
+			err = f.Close()
+			if err != nil {
+				t.Errorf("OpenFile(%q, %#x): Close: %v", test.name, test.flag, err)
+			}
+			continue
+		}
+		if !errors.Is(err, test.want) {
+			t.Errorf("OpenFile(%q, %#x): got %v, want %v", test.name, test.flag, err, test.want)
+		}
+		if f != nil {
+			_ = f.Close()
+		}
+	}
+	if !s.Exists("/share/created.txt") {
+		t.Error("O_CREATE did not create the file")
+	}
+	content, _ := s.ReadFile("/share/hello.txt")
+	if content != "" {
+		t.Errorf("O_TRUNC did not truncate, got %q", content)
+	}
+}
+
+func TestFileWriteAbort(t *testing.T) {
+	a, s := newTestFs(t)
+
+	f, err := a.OpenFile("hello.txt", os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteString("partial")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteAt([]byte("x"), 0)
+	if err == nil {
+		t.Fatal("expected WriteAt before the end to fail")
+	}
+	err = f.Close()
+	if err == nil {
+		t.Fatal("expected Close to report the failed write")
+	}
+	content, _ := s.ReadFile("/share/hello.txt")
+	if content != "hello, world\n" {
+		t.Fatalf("aborted write replaced the file with %q", content)
+	}
+}
+
+func TestFileRead(t *testing.T) {
+	a, _ := newTestFs(t)
+
+	f, err := a.Open("dir/a.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(f afero.File) {
+		_ = f.Close()
+	}(f)
+	_, err = f.Seek(8, io.SeekStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil || string(data) != "a\n" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	buf := make([]byte, 7)
+	_, err = f.ReadAt(buf, 0)
+	if err != nil || string(buf) != "package" {
+		t.Fatalf("got %q, %v", buf, err)
+	}
+	_, err = f.Write([]byte("x"))
+	if err == nil {
+		t.Fatal("expected write to a read handle to fail")
+	}
+
+	d, err := a.Open("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := d.Readdirnames(-1)
+	_ = d.Close()
+	sort.Strings(names)
+	if err != nil || strings.Join(names, ",") != "a.go,sub" {
+		t.Fatalf("got %v, %v", names, err)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	a, _ := newTestFs(t)
+	err := a.Chmod("hello.txt", 0600)
+	if !errors.Is(err, errNotSupported) {
+		t.Errorf("Chmod: got %v", err)
+	}
+	err = a.Chown("hello.txt", 0, 0)
+	if !errors.Is(err, errNotSupported) {
+		t.Errorf("Chown: got %v", err)
+	}
+	mtime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = a.Chtimes("hello.txt", mtime, mtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := a.Stat("hello.txt")
+	if err != nil || !info.ModTime().Equal(mtime) {
+		t.Errorf("Chtimes: got %v, %v", info, err)
+	}
+}
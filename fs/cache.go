@@ -0,0 +1,186 @@
+package fs
+
+import (
+	"container/list"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+type CacheOptions struct {
+	// TTL is how long entries stay valid. Zero means entries only leave the
+	// cache through eviction or invalidation.
+	TTL time.Duration
+	// MaxEntries bounds the number of cached Stat results and directory
+	// listings. Zero means unbounded.
+	MaxEntries int
+}
+
+type cacheEntry struct {
+	key     string
+	dir     bool
+	file    filestation.File
+	files   []filestation.File
+	shares  []filestation.SharedFolder
+	expires time.Time
+}
+
+// metadataCache is an LRU cache of GetInfo results and directory listings,
+// keyed by resolved server path.
+type metadataCache struct {
+	options  CacheOptions
+	mu       sync.Mutex
+	lru      *list.List
+	stats    map[string]*list.Element
+	listings map[string]*list.Element
+}
+
+func newMetadataCache(options CacheOptions) *metadataCache {
+	return &metadataCache{
+		options:  options,
+		lru:      list.New(),
+		stats:    make(map[string]*list.Element),
+		listings: make(map[string]*list.Element),
+	}
+}
+
+func (c *metadataCache) index(dir bool) map[string]*list.Element {
+	if dir {
+		return c.listings
+	}
+	return c.stats
+}
+
+func (c *metadataCache) get(key string, dir bool) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.index(dir)[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry, true
+}
+
+func (c *metadataCache) put(entry *cacheEntry) {
+	if c.options.TTL > 0 {
+		entry.expires = time.Now().Add(c.options.TTL)
+	}
+	index := c.index(entry.dir)
+	if element, ok := index[entry.key]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+	} else {
+		index[entry.key] = c.lru.PushFront(entry)
+	}
+	for c.options.MaxEntries > 0 && c.lru.Len() > c.options.MaxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *metadataCache) remove(element *list.Element) {
+	entry := element.Value.(*cacheEntry)
+	delete(c.index(entry.dir), entry.key)
+	c.lru.Remove(element)
+}
+
+func (c *metadataCache) getStat(key string) (filestation.File, bool) {
+	entry, ok := c.get(key, false)
+	if !ok {
+		return filestation.File{}, false
+	}
+	return entry.file, true
+}
+
+func (c *metadataCache) putStat(key string, file filestation.File) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.put(&cacheEntry{
+		key:  key,
+		file: file,
+	})
+}
+
+func (c *metadataCache) getListing(key string) ([]filestation.File, bool) {
+	entry, ok := c.get(key, true)
+	if !ok {
+		return nil, false
+	}
+	return entry.files, true
+}
+
+// putListing caches a directory listing along with the Stat result of each
+// child.
+func (c *metadataCache) putListing(key string, files []filestation.File) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, file := range files {
+		c.put(&cacheEntry{
+			key:  file.Path,
+			file: file,
+		})
+	}
+	c.put(&cacheEntry{
+		key:   key,
+		dir:   true,
+		files: files,
+	})
+}
+
+func (c *metadataCache) getShares() ([]filestation.SharedFolder, bool) {
+	entry, ok := c.get("/", true)
+	if !ok {
+		return nil, false
+	}
+	return entry.shares, true
+}
+
+// putShares caches the shared folders, which form the listing of the root
+// directory.
+func (c *metadataCache) putShares(shares []filestation.SharedFolder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.put(&cacheEntry{
+		key:    "/",
+		dir:    true,
+		shares: shares,
+	})
+}
+
+// invalidate drops key, everything below it, and the listing of its parent.
+func (c *metadataCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix := strings.TrimSuffix(key, "/") + "/"
+	for _, index := range []map[string]*list.Element{c.stats, c.listings} {
+		for k, element := range index {
+			if k == key || strings.HasPrefix(k, prefix) {
+				c.remove(element)
+			}
+		}
+	}
+	if element, ok := c.listings[path.Dir(key)]; ok {
+		c.remove(element)
+	}
+}
+
+func (c *metadataCache) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.Init()
+	c.stats = make(map[string]*list.Element)
+	c.listings = make(map[string]*list.Element)
+}
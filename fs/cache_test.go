@@ -0,0 +1,266 @@
+package fs
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	getInfoMethod = "SYNO.FileStation.List.getinfo"
+	listMethod    = "SYNO.FileStation.List.list"
+)
+
+func readDirNames(t *testing.T, f fs.ReadDirFS, name string) string {
+	t.Helper()
+	dirEntries, err := f.ReadDir(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		names = append(names, dirEntry.Name())
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func assertReadDir(t *testing.T, f fs.ReadDirFS, name string, want string) {
+	t.Helper()
+	got := readDirNames(t, f, name)
+	if got != want {
+		t.Fatalf("ReadDir(%q): got %s, want %s", name, got, want)
+	}
+}
+
+func assertNotExist(t *testing.T, f fs.StatFS, name string) {
+	t.Helper()
+	_, err := f.Stat(name)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Stat(%q): got %v, want %v", name, err, fs.ErrNotExist)
+	}
+}
+
+func assertSize(t *testing.T, f fs.StatFS, name string, want int64) {
+	t.Helper()
+	fileInfo, err := f.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fileInfo.Size() != want {
+		t.Fatalf("Stat(%q): got size %d, want %d", name, fileInfo.Size(), want)
+	}
+}
+
+func TestCacheHit(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	f.EnableCache(CacheOptions{})
+
+	assertReadDir(t, f, "dir", "a.go,b.txt,sub")
+	assertSize(t, f, "dir/a.go", 10)
+	assertReadDir(t, f, "dir", "a.go,b.txt,sub")
+	if n := s.Calls(listMethod); n != 1 {
+		t.Fatalf("got %d list calls, want 1", n)
+	}
+	if n := s.Calls(getInfoMethod); n != 1 {
+		// only NewFS stats the root, the listing populated dir/a.go
+		t.Fatalf("got %d getinfo calls, want 1", n)
+	}
+}
+
+func TestCacheWritableFS(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestWritableFS(t, s, "/share")
+	f.EnableCache(CacheOptions{})
+
+	assertReadDir(t, f, ".", "big.bin,dir,empty,hello.txt")
+	assertReadDir(t, f, "dir", "a.go,b.txt,sub")
+	assertSize(t, f, "hello.txt", 13)
+
+	err := f.WriteFile("new.txt", []byte("new"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadDir(t, f, ".", "big.bin,dir,empty,hello.txt,new.txt")
+	assertSize(t, f, "new.txt", 3)
+
+	err = f.WriteFile("hello.txt", []byte("hi"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSize(t, f, "hello.txt", 2)
+
+	err = f.Remove("new.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadDir(t, f, ".", "big.bin,dir,empty,hello.txt")
+	assertNotExist(t, f, "new.txt")
+
+	err = f.Rename("hello.txt", "dir/renamed.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadDir(t, f, ".", "big.bin,dir,empty")
+	assertReadDir(t, f, "dir", "a.go,b.txt,renamed.txt,sub")
+	assertNotExist(t, f, "hello.txt")
+	assertSize(t, f, "dir/renamed.txt", 2)
+
+	err = f.MkdirAll("x/y/z", 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadDir(t, f, ".", "big.bin,dir,empty,x")
+	assertReadDir(t, f, "x/y", "z")
+
+	err = f.RemoveAll("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadDir(t, f, ".", "big.bin,empty,x")
+	assertNotExist(t, f, "dir/a.go")
+}
+
+func TestCacheWritableFSSub(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestWritableFS(t, s, "/share")
+	f.EnableCache(CacheOptions{})
+
+	fsys, err := f.Sub("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := fsys.(*WritableFS)
+
+	assertReadDir(t, f, "dir", "a.go,b.txt,sub")
+	assertReadDir(t, sub, ".", "a.go,b.txt,sub")
+	assertReadDir(t, sub, "sub", "c.md,deep")
+
+	// changes made through the sub are seen by the parent
+	err = sub.WriteFile("new.txt", []byte("new"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadDir(t, f, "dir", "a.go,b.txt,new.txt,sub")
+	assertSize(t, f, "dir/new.txt", 3)
+
+	err = sub.Rename("sub/c.md", "c.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadDir(t, f, "dir/sub", "deep")
+	assertReadDir(t, f, "dir", "a.go,b.txt,c.md,new.txt,sub")
+
+	err = sub.MkdirAll("x/y", 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadDir(t, f, "dir", "a.go,b.txt,c.md,new.txt,sub,x")
+
+	// changes made through the parent are seen by the sub
+	err = f.Remove("dir/a.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadDir(t, sub, ".", "b.txt,c.md,new.txt,sub,x")
+	assertNotExist(t, sub, "a.go")
+
+	err = f.RemoveAll("dir/sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadDir(t, sub, ".", "b.txt,c.md,new.txt,x")
+	assertNotExist(t, sub, "sub/deep/d.x")
+}
+
+func TestCacheTTL(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	f.EnableCache(CacheOptions{
+		TTL: 100 * time.Millisecond,
+	})
+
+	assertSize(t, f, "hello.txt", 13)
+	assertReadDir(t, f, "empty", "")
+	s.WriteFile("/share/hello.txt", "hi")
+	s.WriteFile("/share/empty/new.txt", "new")
+	assertSize(t, f, "hello.txt", 13)
+	assertReadDir(t, f, "empty", "")
+
+	time.Sleep(150 * time.Millisecond)
+	assertSize(t, f, "hello.txt", 2)
+	assertReadDir(t, f, "empty", "new.txt")
+}
+
+func TestCacheMaxEntries(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	f.EnableCache(CacheOptions{
+		MaxEntries: 2,
+	})
+
+	calls := s.Calls(getInfoMethod)
+	assertCalls := func(want int) {
+		t.Helper()
+		if n := s.Calls(getInfoMethod) - calls; n != want {
+			t.Fatalf("got %d getinfo calls, want %d", n, want)
+		}
+	}
+
+	assertSize(t, f, "hello.txt", 13)
+	assertSize(t, f, "dir/a.go", 10)
+	assertCalls(2)
+	assertSize(t, f, "hello.txt", 13)
+	assertSize(t, f, "dir/a.go", 10)
+	assertCalls(2)
+
+	// evicts hello.txt, the least recently used entry
+	assertSize(t, f, "dir/b.txt", 4)
+	assertCalls(3)
+	assertSize(t, f, "dir/a.go", 10)
+	assertCalls(3)
+	assertSize(t, f, "hello.txt", 13)
+	assertCalls(4)
+
+	// a listing and the entries it populates count against the limit
+	assertReadDir(t, f, "dir/sub/deep", "d.x")
+	assertSize(t, f, "dir/a.go", 10)
+	assertCalls(5)
+}
+
+func TestCacheInvalidateAll(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	f.EnableCache(CacheOptions{})
+	err := f.EnableContentCache(ContentCacheOptions{
+		Dir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := f.ReadFile("hello.txt")
+	if err != nil || string(data) != "hello, world\n" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	assertReadDir(t, f, "empty", "")
+
+	// same size and mtime, so only invalidation reveals the change
+	s.WriteFile("/share/hello.txt", "HELLO, WORLD\n")
+	s.WriteFile("/share/empty/new.txt", "new")
+	data, err = f.ReadFile("hello.txt")
+	if err != nil || string(data) != "hello, world\n" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+
+	f.InvalidateAll()
+	data, err = f.ReadFile("hello.txt")
+	if err != nil || string(data) != "HELLO, WORLD\n" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	assertReadDir(t, f, "empty", "new.txt")
+}
@@ -0,0 +1,365 @@
+package fs
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	contentCacheTempPrefix = ".tmp-"
+	// contentCachePathSuffix names the file next to each entry that records
+	// the server path it was downloaded from.
+	contentCachePathSuffix = ".path"
+)
+
+var (
+	errShortDownload = errors.New("download shorter than file size")
+)
+
+type ContentCacheOptions struct {
+	// Dir is the local directory holding cached file contents. It is created
+	// if it does not exist and may be shared across runs.
+	Dir string
+	// MaxBytes bounds the total size of cached contents. Files larger than
+	// MaxBytes are never cached. Zero means unbounded.
+	MaxBytes int64
+}
+
+type contentCacheEntry struct {
+	key  string
+	path string
+	size int64
+}
+
+// contentCache is an LRU cache of file contents on local disk. Entries are
+// keyed by server path, size and mtime, so a changed file never matches a
+// stale entry.
+type contentCache struct {
+	options ContentCacheOptions
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	size    int64
+}
+
+func newContentCache(options ContentCacheOptions) (*contentCache, error) {
+	if options.Dir == "" {
+		return nil, fs.ErrInvalid
+	}
+	err := os.MkdirAll(options.Dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	c := &contentCache{
+		options: options,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+
+	dirEntries, err := os.ReadDir(options.Dir)
+	if err != nil {
+		return nil, err
+	}
+	type diskEntry struct {
+		fileInfo fs.FileInfo
+		path     string
+	}
+	var diskEntries []diskEntry
+	keys := make(map[string]bool)
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if !dirEntry.Type().IsRegular() || strings.HasSuffix(name, contentCachePathSuffix) {
+			continue
+		}
+		if strings.HasPrefix(name, contentCacheTempPrefix) {
+			// left behind by an interrupted download
+			_ = os.Remove(filepath.Join(options.Dir, name))
+			continue
+		}
+		pathBytes, err := os.ReadFile(filepath.Join(options.Dir, name+contentCachePathSuffix))
+		if err != nil {
+			// the server path is unknown, so the entry could never be invalidated
+			_ = os.Remove(filepath.Join(options.Dir, name))
+			continue
+		}
+		fileInfo, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		diskEntries = append(diskEntries, diskEntry{
+			fileInfo: fileInfo,
+			path:     string(pathBytes),
+		})
+		keys[name] = true
+	}
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if strings.HasSuffix(name, contentCachePathSuffix) && !keys[strings.TrimSuffix(name, contentCachePathSuffix)] {
+			_ = os.Remove(filepath.Join(options.Dir, name))
+		}
+	}
+	// entry mtimes are bumped on access, so the most recently used come last
+	sort.Slice(diskEntries, func(i, j int) bool {
+		return diskEntries[i].fileInfo.ModTime().Before(diskEntries[j].fileInfo.ModTime())
+	})
+	latest := make(map[string]*list.Element)
+	for _, diskEntry := range diskEntries {
+		if element, ok := latest[diskEntry.path]; ok {
+			// an older version of the file, superseded by a later download
+			c.remove(element)
+		}
+		entry := &contentCacheEntry{
+			key:  diskEntry.fileInfo.Name(),
+			path: diskEntry.path,
+			size: diskEntry.fileInfo.Size(),
+		}
+		c.add(entry)
+		latest[entry.path] = c.entries[entry.key]
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict(nil)
+	return c, nil
+}
+
+func contentCacheKey(resolvedPath string, size int64, mtime time.Time) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%d", resolvedPath, size, mtime.Unix())))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *contentCache) accepts(size int64) bool {
+	return c.options.MaxBytes <= 0 || size <= c.options.MaxBytes
+}
+
+func (c *contentCache) filename(key string) string {
+	return filepath.Join(c.options.Dir, key)
+}
+
+// open returns the cached content for key, if present.
+func (c *contentCache) open(key string) (*os.File, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	file, err := os.Open(c.filename(key))
+	if err != nil {
+		c.remove(element)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	now := time.Now()
+	_ = os.Chtimes(file.Name(), now, now)
+	return file, true
+}
+
+// populate stores the content read from r under key. The content is written
+// to a temporary file and renamed into place, so readers never observe a
+// partial entry.
+func (c *contentCache) populate(key string, resolvedPath string, size int64, r io.Reader) error {
+	tempFile, err := os.CreateTemp(c.options.Dir, contentCacheTempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer func(name string) {
+		_ = os.Remove(name)
+	}(tempFile.Name())
+
+	n, err := io.Copy(tempFile, r)
+	if err != nil {
+		_ = tempFile.Close()
+		return err
+	}
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return errShortDownload
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err = os.WriteFile(c.filename(key)+contentCachePathSuffix, []byte(resolvedPath), 0o644)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tempFile.Name(), c.filename(key))
+	if err != nil {
+		_ = os.Remove(c.filename(key) + contentCachePathSuffix)
+		return err
+	}
+	for k, element := range c.entries {
+		entry := element.Value.(*contentCacheEntry)
+		if k != key && entry.path == resolvedPath {
+			// superseded by the new version of the file
+			c.remove(element)
+		}
+	}
+	entry := &contentCacheEntry{
+		key:  key,
+		path: resolvedPath,
+		size: n,
+	}
+	c.add(entry)
+	c.evict(entry)
+	return nil
+}
+
+// invalidate drops the entries of resolvedPath and everything below it.
+func (c *contentCache) invalidate(resolvedPath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix := strings.TrimSuffix(resolvedPath, "/") + "/"
+	for _, element := range c.entries {
+		entry := element.Value.(*contentCacheEntry)
+		if entry.path == resolvedPath || strings.HasPrefix(entry.path, prefix) {
+			c.remove(element)
+		}
+	}
+}
+
+// invalidateAll drops every entry.
+func (c *contentCache) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, element := range c.entries {
+		c.remove(element)
+	}
+}
+
+func (c *contentCache) add(entry *contentCacheEntry) {
+	if element, ok := c.entries[entry.key]; ok {
+		c.size -= element.Value.(*contentCacheEntry).size
+		element.Value = entry
+		c.lru.MoveToFront(element)
+	} else {
+		c.entries[entry.key] = c.lru.PushFront(entry)
+	}
+	c.size += entry.size
+}
+
+// evict removes least recently used entries until the cache fits within
+// MaxBytes, keeping the given entry. The caller must hold mu.
+func (c *contentCache) evict(keep *contentCacheEntry) {
+	if c.options.MaxBytes <= 0 {
+		return
+	}
+	element := c.lru.Back()
+	for element != nil && c.size > c.options.MaxBytes {
+		prev := element.Prev()
+		if element.Value != keep {
+			c.remove(element)
+		}
+		element = prev
+	}
+}
+
+func (c *contentCache) remove(element *list.Element) {
+	entry := element.Value.(*contentCacheEntry)
+	delete(c.entries, entry.key)
+	c.lru.Remove(element)
+	c.size -= entry.size
+	_ = os.Remove(c.filename(entry.key))
+	_ = os.Remove(c.filename(entry.key) + contentCachePathSuffix)
+}
+
+// ----
+
+// openCached serves the named file from the content cache, downloading it
+// into the cache first on a miss. If the metadata cache is enabled, info is
+// refreshed from the server so that stale contents are never served.
+func (f *FS) openCached(name string, info *dirEntry) (*cachedFile, error) {
+	if f.cache != nil {
+		var err error
+		info, err = f.statRemote(name)
+		if err != nil {
+			return nil, err
+		}
+		if info.file.IsDir || !f.contentCache.accepts(info.Size()) {
+			return nil, fs.ErrInvalid
+		}
+	}
+	resolvedPath := f.resolvePath(name)
+	key := contentCacheKey(resolvedPath, info.Size(), info.ModTime())
+	file, ok := f.contentCache.open(key)
+	if !ok {
+		r, err := f.fileStationApi.Download(filestation.DownloadRequest{
+			Path: []string{resolvedPath},
+			Mode: filestation.DownloadModeDownload,
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = f.contentCache.populate(key, resolvedPath, info.Size(), r)
+		_ = r.Close()
+		if err != nil {
+			return nil, err
+		}
+		file, ok = f.contentCache.open(key)
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+	}
+	return &cachedFile{
+		name: name,
+		file: file,
+		info: info,
+	}, nil
+}
+
+// cachedFile is a read-only view of a content cache entry. Errors refer to
+// name rather than the local cache file.
+type cachedFile struct {
+	name string
+	file *os.File
+	info *dirEntry
+}
+
+func (c *cachedFile) pathError(err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return &fs.PathError{Op: pathErr.Op, Path: c.name, Err: pathErr.Err}
+	}
+	return err
+}
+
+func (c *cachedFile) Read(p []byte) (int, error) {
+	n, err := c.file.Read(p)
+	return n, c.pathError(err)
+}
+
+func (c *cachedFile) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.file.ReadAt(p, off)
+	return n, c.pathError(err)
+}
+
+func (c *cachedFile) Seek(offset int64, whence int) (int64, error) {
+	n, err := c.file.Seek(offset, whence)
+	return n, c.pathError(err)
+}
+
+func (c *cachedFile) Close() error {
+	return c.pathError(c.file.Close())
+}
+
+func (c *cachedFile) Stat() (fs.FileInfo, error) {
+	return c.info, nil
+}
@@ -0,0 +1,221 @@
+package fs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+const downloadMethod = "SYNO.FileStation.Download.download"
+
+func assertReadFile(t *testing.T, f fs.FS, name string, want string) {
+	t.Helper()
+	data, err := fs.ReadFile(f, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		t.Fatalf("ReadFile(%q): got %q, want %q", name, data, want)
+	}
+}
+
+func TestContentCache(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	err := f.EnableContentCache(ContentCacheOptions{
+		Dir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fstest.TestFS(f, testFileNames...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := s.Calls(downloadMethod)
+	assertReadFile(t, f, "hello.txt", "hello, world\n")
+	assertReadFile(t, f, "dir/a.go", "package a\n")
+	if n := s.Calls(downloadMethod); n != calls {
+		t.Fatalf("got %d downloads, want %d", n, calls)
+	}
+}
+
+func TestContentCacheStaleMetadata(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	f.EnableCache(CacheOptions{})
+	err := f.EnableContentCache(ContentCacheOptions{
+		Dir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertReadFile(t, f, "hello.txt", "hello, world\n")
+	s.WriteFile("/share/hello.txt", "hi")
+	// the metadata cache still reports the old size, but the content is
+	// validated against the server
+	assertSize(t, f, "hello.txt", 13)
+	assertReadFile(t, f, "hello.txt", "hi")
+
+	file, err := f.Open("hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(file fs.File) {
+		_ = file.Close()
+	}(file)
+	fileInfo, err := file.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fileInfo.Size() != 2 {
+		t.Fatalf("got size %d, want 2", fileInfo.Size())
+	}
+}
+
+func TestContentCacheFallback(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	cacheDir := t.TempDir()
+	err := f.EnableContentCache(ContentCacheOptions{
+		Dir: cacheDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// entries can no longer be written
+	err = os.RemoveAll(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReadFile(t, f, "hello.txt", "hello, world\n")
+
+	file, err := f.Open("dir/a.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(file fs.File) {
+		_ = file.Close()
+	}(file)
+	if _, ok := file.(*cachedFile); ok {
+		t.Fatal("expected a direct read")
+	}
+	buf := make([]byte, 7)
+	_, err = file.(io.ReaderAt).ReadAt(buf, 0)
+	if err != nil || string(buf) != "package" {
+		t.Fatalf("got %q, %v", buf, err)
+	}
+}
+
+func TestContentCacheFileClosed(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	err := f.EnableContentCache(ContentCacheOptions{
+		Dir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := f.Open("hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := file.(*cachedFile); !ok {
+		t.Fatalf("got %T, want a cached file", file)
+	}
+	if _, ok := file.(io.Writer); ok {
+		t.Fatal("cached file must be read-only")
+	}
+	err = file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = file.Read(make([]byte, 1))
+	var pathErr *fs.PathError
+	if !errors.Is(err, fs.ErrClosed) || !errors.As(err, &pathErr) || pathErr.Path != "hello.txt" {
+		t.Fatalf("got %v, want %v for hello.txt", err, fs.ErrClosed)
+	}
+}
+
+func cacheDirNames(t *testing.T, dir string) []string {
+	t.Helper()
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, dirEntry := range dirEntries {
+		names = append(names, dirEntry.Name())
+	}
+	return names
+}
+
+// TestContentCacheRestart checks that entries found on disk at startup are
+// superseded and invalidated like those populated by the running process.
+func TestContentCacheRestart(t *testing.T) {
+	s := newTestServer(t)
+	cacheDir := t.TempDir()
+	newCachedFS := func() *FS {
+		f := newTestFS(t, s, "/share")
+		err := f.EnableContentCache(ContentCacheOptions{
+			Dir: cacheDir,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	f := newCachedFS()
+	assertReadFile(t, f, "hello.txt", "hello, world\n")
+	assertReadFile(t, f, "dir/a.go", "package a\n")
+	if names := cacheDirNames(t, cacheDir); len(names) != 4 {
+		t.Fatalf("got %v, want two entries and their paths", names)
+	}
+
+	// a new version of hello.txt supersedes the one from the previous run
+	s.WriteFile("/share/hello.txt", "hi")
+	f = newCachedFS()
+	calls := s.Calls(downloadMethod)
+	assertReadFile(t, f, "dir/a.go", "package a\n")
+	if n := s.Calls(downloadMethod); n != calls {
+		t.Fatal("entry from the previous run was not used")
+	}
+	assertReadFile(t, f, "hello.txt", "hi")
+	if names := cacheDirNames(t, cacheDir); len(names) != 4 {
+		t.Fatalf("got %v, want two entries and their paths", names)
+	}
+
+	f = newCachedFS()
+	f.Invalidate("dir")
+	if names := cacheDirNames(t, cacheDir); len(names) != 2 {
+		t.Fatalf("got %v, want one entry and its path", names)
+	}
+}
+
+func TestContentCacheUnknownPath(t *testing.T) {
+	cacheDir := t.TempDir()
+	for _, name := range []string{"0123", "4567" + contentCachePathSuffix, contentCacheTempPrefix + "89ab"} {
+		err := os.WriteFile(filepath.Join(cacheDir, name), []byte("x"), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := newContentCache(ContentCacheOptions{
+		Dir: cacheDir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := cacheDirNames(t, cacheDir); len(names) != 0 {
+		t.Fatalf("got %v, want files without a known path removed", names)
+	}
+}
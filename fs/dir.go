@@ -0,0 +1,123 @@
+package fs
+
+import (
+	"errors"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"io"
+	"io/fs"
+)
+
+var (
+	errIsDirectory = errors.New("is a directory")
+)
+
+type dir struct {
+	fs     *FS
+	path   string
+	info   *dirEntry
+	it     *filestation.Iterator[filestation.File]
+	files  []*dirEntry
+	cached bool
+	listed []filestation.File
+	closed bool
+}
+
+func openDir(f *FS, name string, info *dirEntry) *dir {
+	return &dir{
+		fs:   f,
+		path: name,
+		info: info,
+	}
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: errIsDirectory}
+}
+
+func (d *dir) Close() error {
+	if d.closed {
+		return &fs.PathError{Op: "close", Path: d.path, Err: fs.ErrClosed}
+	}
+	d.closed = true
+	d.it = nil
+	d.files = nil
+	d.listed = nil
+	return nil
+}
+
+// ReadDir lists the directory one page at a time, following the semantics of
+// fs.ReadDirFile. A complete listing is stored in the metadata cache, if
+// enabled, and served from it when present.
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: d.path, Err: fs.ErrClosed}
+	}
+	if d.it == nil && !d.cached {
+		resolvedPath := d.fs.resolvePath(d.path)
+		if d.fs.isRoot() && resolvedPath == "/" {
+			shareEntries, err := d.fs.shareEntries()
+			if err != nil {
+				return nil, pathError("readdir", d.path, err)
+			}
+			d.files, d.cached = shareEntries, true
+		} else if d.fs.cache != nil {
+			var files []filestation.File
+			files, d.cached = d.fs.cache.getListing(resolvedPath)
+			for _, file := range files {
+				d.files = append(d.files, &dirEntry{
+					file: file,
+				})
+			}
+		}
+		if !d.cached {
+			d.it = d.fs.fileStationApi.ListIter(filestation.ListRequest{
+				FolderPath: resolvedPath,
+				Additional: d.fs.additional(),
+			})
+		}
+	}
+
+	dirEntries := make([]fs.DirEntry, 0)
+	for n <= 0 || len(dirEntries) < n {
+		entry, ok := d.next()
+		if !ok {
+			break
+		}
+		dirEntries = append(dirEntries, entry)
+	}
+	if d.it != nil && d.it.Err() != nil {
+		return dirEntries, pathError("readdir", d.path, d.it.Err())
+	}
+	if n > 0 && len(dirEntries) == 0 {
+		return dirEntries, io.EOF
+	}
+	return dirEntries, nil
+}
+
+func (d *dir) next() (*dirEntry, bool) {
+	if d.cached {
+		if len(d.files) == 0 {
+			return nil, false
+		}
+		entry := d.files[0]
+		d.files = d.files[1:]
+		return entry, true
+	}
+	if !d.it.Next() {
+		if d.it.Err() == nil && d.fs.cache != nil && d.listed != nil {
+			d.fs.cache.putListing(d.fs.resolvePath(d.path), d.listed)
+			d.listed = nil
+		}
+		return nil, false
+	}
+	if d.fs.cache != nil {
+		d.listed = append(d.listed, d.it.Value())
+	}
+	return &dirEntry{
+		file: d.it.Value(),
+	}, true
+}
@@ -0,0 +1,52 @@
+package fs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestDirClosed(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	root := newTestFS(t, s, "/")
+	cached := newTestFS(t, s, "/share")
+	cached.EnableCache(CacheOptions{})
+	_, err := cached.ReadDir("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		fs   *FS
+		dir  string
+	}{
+		{"listed", f, "dir"},
+		{"cached", cached, "dir"},
+		{"root", root, "."},
+	}
+	for _, test := range tests {
+		file, err := test.fs.Open(test.dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := file.(fs.ReadDirFile)
+		_, err = d.ReadDir(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = d.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dirEntries, err := d.ReadDir(-1)
+		if !errors.Is(err, fs.ErrClosed) || len(dirEntries) != 0 {
+			t.Errorf("%s: ReadDir after Close: got %d entries, %v", test.name, len(dirEntries), err)
+		}
+		err = d.Close()
+		if !errors.Is(err, fs.ErrClosed) {
+			t.Errorf("%s: second Close: got %v, want %v", test.name, err, fs.ErrClosed)
+		}
+	}
+}
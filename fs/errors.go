@@ -0,0 +1,40 @@
+package fs
+
+import (
+	"errors"
+	"github.com/ngyewch/go-syno/api"
+	"io/fs"
+)
+
+// mapError translates FileStation error codes to io/fs sentinel errors. The
+// sentinel is returned as is, rather than wrapped, so that os.IsNotExist and
+// friends recognise it.
+func mapError(err error) error {
+	var e *api.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	codes := []int{e.Code}
+	for _, entry := range e.Errors {
+		codes = append(codes, entry.Code)
+	}
+	for _, code := range codes {
+		switch code {
+		case 408:
+			return fs.ErrNotExist
+		case 105, 407:
+			return fs.ErrPermission
+		case 414:
+			return fs.ErrExist
+		}
+	}
+	return err
+}
+
+func pathError(op string, name string, err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return err
+	}
+	return &fs.PathError{Op: op, Path: name, Err: mapError(err)}
+}
@@ -0,0 +1,364 @@
+package fs
+
+import (
+	"bufio"
+	"errors"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+const (
+	spoolTempPrefix = "go-syno-spool-"
+	// readAheadSize is the buffer size for sequential reads and the minimum
+	// block size fetched by ReadAt.
+	readAheadSize = 256 * 1024
+	// maxSkipSize is the largest forward seek served by discarding from the
+	// current stream instead of issuing a new ranged download.
+	maxSkipSize = 256 * 1024
+)
+
+var (
+	errNegativeOffset = errors.New("negative offset")
+	errInvalidWhence  = errors.New("invalid whence")
+)
+
+type file struct {
+	fs   *FS
+	path string
+
+	// state shared by Read and ReadAt
+	stateMu sync.Mutex
+	size    int64
+	noRange bool
+	closed  bool
+
+	r       io.ReadCloser
+	br      *bufio.Reader
+	rOffset int64
+	offset  int64
+
+	mu          sync.Mutex
+	block       []byte
+	blockOffset int64
+
+	// local copy of the file used by ReadAt if the server ignores ranges
+	spool         *os.File
+	spoolSize     int64
+	spoolSrc      io.ReadCloser
+	spoolComplete bool
+}
+
+func openFile(f *FS, name string, size int64) *file {
+	return &file{
+		fs:   f,
+		path: name,
+		size: size,
+	}
+}
+
+func (f *file) download(offset int64, length int64) (*api.RawResponse, error) {
+	res, err := f.fs.fileStationApi.Download(filestation.DownloadRequest{
+		Path:   []string{f.fs.resolvePath(f.path)},
+		Mode:   filestation.DownloadModeDownload,
+		Offset: offset,
+		Length: length,
+	})
+	if err != nil {
+		return nil, err
+	}
+	f.stateMu.Lock()
+	defer f.stateMu.Unlock()
+	if res.IsPartial() {
+		_, _, size, ok := res.ContentRange()
+		if ok && size >= 0 {
+			f.size = size
+		}
+	} else {
+		if offset > 0 || length > 0 {
+			// ranges not supported, the whole file is returned
+			f.noRange = true
+		}
+		if res.ContentLength >= 0 {
+			f.size = res.ContentLength
+		}
+	}
+	return res, nil
+}
+
+func (f *file) state() (size int64, noRange bool, closed bool) {
+	f.stateMu.Lock()
+	defer f.stateMu.Unlock()
+	return f.size, f.noRange, f.closed
+}
+
+func (f *file) openStream(offset int64) error {
+	f.closeStream()
+	_, noRange, _ := f.state()
+	if noRange {
+		offset = 0
+	}
+	res, err := f.download(offset, 0)
+	if err != nil {
+		return err
+	}
+	f.r = res
+	f.br = bufio.NewReaderSize(res, readAheadSize)
+	if res.IsPartial() {
+		f.rOffset = offset
+	} else {
+		f.rOffset = 0
+	}
+	return nil
+}
+
+func (f *file) closeStream() {
+	if f.r != nil {
+		_ = f.r.Close()
+		f.r = nil
+		f.br = nil
+	}
+}
+
+func (f *file) seekStream() error {
+	_, noRange, _ := f.state()
+	if f.r != nil && f.offset >= f.rOffset && (noRange || f.offset-f.rOffset <= maxSkipSize) {
+		return f.discard()
+	}
+	err := f.openStream(f.offset)
+	if err != nil {
+		return err
+	}
+	return f.discard()
+}
+
+func (f *file) discard() error {
+	if f.rOffset >= f.offset {
+		return nil
+	}
+	n, err := io.CopyN(io.Discard, f.br, f.offset-f.rOffset)
+	f.rOffset += n
+	return err
+}
+
+func (f *file) Close() error {
+	f.stateMu.Lock()
+	closed := f.closed
+	f.closed = true
+	f.stateMu.Unlock()
+	if closed {
+		return &fs.PathError{Op: "close", Path: f.path, Err: fs.ErrClosed}
+	}
+	f.closeSpool()
+	if f.r == nil {
+		return nil
+	}
+	err := f.r.Close()
+	f.r = nil
+	f.br = nil
+	return err
+}
+
+func (f *file) Read(p []byte) (int, error) {
+	size, _, closed := f.state()
+	if closed {
+		return 0, &fs.PathError{Op: "read", Path: f.path, Err: fs.ErrClosed}
+	}
+	if size >= 0 && f.offset >= size {
+		return 0, io.EOF
+	}
+	err := f.seekStream()
+	if err != nil {
+		return 0, pathError("read", f.path, err)
+	}
+	n, err := f.br.Read(p)
+	f.offset += int64(n)
+	f.rOffset += int64(n)
+	if err != nil && err != io.EOF {
+		return n, pathError("read", f.path, err)
+	}
+	return n, err
+}
+
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	size, _, closed := f.state()
+	if closed {
+		return 0, &fs.PathError{Op: "seek", Path: f.path, Err: fs.ErrClosed}
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		if size < 0 {
+			fileInfo, err := f.Stat()
+			if err != nil {
+				return 0, err
+			}
+			size = fileInfo.Size()
+			f.stateMu.Lock()
+			f.size = size
+			f.stateMu.Unlock()
+		}
+		offset += size
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.path, Err: errInvalidWhence}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.path, Err: errNegativeOffset}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// ReadAt reads from a block cache filled by ranged downloads. It does not use
+// or affect the offset of Read and Seek.
+func (f *file) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, &fs.PathError{Op: "readat", Path: f.path, Err: errNegativeOffset}
+	}
+	_, _, closed := f.state()
+	if closed {
+		return 0, &fs.PathError{Op: "readat", Path: f.path, Err: fs.ErrClosed}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		_, noRange, _ := f.state()
+		if noRange {
+			m, err := f.readSpool(p[n:], pos)
+			return n + m, err
+		}
+		if pos >= f.blockOffset && pos < f.blockOffset+int64(len(f.block)) {
+			n += copy(p[n:], f.block[pos-f.blockOffset:])
+			continue
+		}
+		size, _, _ := f.state()
+		if size >= 0 && pos >= size {
+			return n, io.EOF
+		}
+		length := len(p) - n
+		if length < readAheadSize {
+			length = readAheadSize
+		}
+		err := f.fetchBlock(pos, int64(length))
+		if err != nil {
+			return n, pathError("read", f.path, err)
+		}
+		if len(f.block) == 0 {
+			if _, noRange, _ := f.state(); noRange {
+				continue
+			}
+			return n, io.EOF
+		}
+	}
+	return n, nil
+}
+
+func (f *file) fetchBlock(offset int64, length int64) error {
+	res, err := f.download(offset, length)
+	if err != nil {
+		return err
+	}
+	if !res.IsPartial() {
+		// the server ignored the range, keep the whole file for later reads
+		f.block = nil
+		return f.startSpool(res)
+	}
+	defer func(res io.ReadCloser) {
+		_ = res.Close()
+	}(res)
+
+	block := make([]byte, length)
+	n, err := io.ReadFull(res, block)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	f.block = block[:n]
+	f.blockOffset = offset
+	return nil
+}
+
+// startSpool starts copying the complete download res to a local temporary
+// file, from which readSpool serves reads at any offset. The caller must hold
+// mu.
+func (f *file) startSpool(res io.ReadCloser) error {
+	if f.spool == nil {
+		spool, err := os.CreateTemp("", spoolTempPrefix+"*")
+		if err != nil {
+			_ = res.Close()
+			return err
+		}
+		f.spool = spool
+		f.spoolSize = 0
+	}
+	if f.spoolSize > 0 {
+		// resume an interrupted copy
+		_, err := io.CopyN(io.Discard, res, f.spoolSize)
+		if err != nil {
+			_ = res.Close()
+			return err
+		}
+	}
+	f.spoolSrc = res
+	return nil
+}
+
+// readSpool reads from the local copy of the file, copying more of the
+// download only as far as needed. The caller must hold mu.
+func (f *file) readSpool(p []byte, off int64) (int, error) {
+	end := off + int64(len(p))
+	if !f.spoolComplete && f.spoolSize < end {
+		if f.spoolSrc == nil {
+			res, err := f.download(0, 0)
+			if err != nil {
+				return 0, pathError("read", f.path, err)
+			}
+			err = f.startSpool(res)
+			if err != nil {
+				return 0, pathError("read", f.path, err)
+			}
+		}
+		n, err := io.CopyN(f.spool, f.spoolSrc, end-f.spoolSize)
+		f.spoolSize += n
+		if err != nil {
+			_ = f.spoolSrc.Close()
+			f.spoolSrc = nil
+			if err != io.EOF {
+				return 0, pathError("read", f.path, err)
+			}
+			f.spoolComplete = true
+		}
+	}
+	n, err := f.spool.ReadAt(p, off)
+	if err != nil && err != io.EOF {
+		return n, pathError("read", f.path, err)
+	}
+	return n, err
+}
+
+func (f *file) closeSpool() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.spoolSrc != nil {
+		_ = f.spoolSrc.Close()
+		f.spoolSrc = nil
+	}
+	if f.spool != nil {
+		_ = f.spool.Close()
+		_ = os.Remove(f.spool.Name())
+		f.spool = nil
+	}
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return f.fs.Stat(f.path)
+}
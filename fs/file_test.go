@@ -0,0 +1,153 @@
+package fs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestFileSeekAndReadAt(t *testing.T) {
+	for _, noRange := range []bool{false, true} {
+		s := newTestServer(t)
+		s.SetNoRange(noRange)
+		f := newTestFS(t, s, "/share")
+		want := []byte(testFiles["/share/big.bin"])
+
+		file, err := f.Open("big.bin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		seeker := file.(io.ReadSeeker)
+		readerAt := file.(io.ReaderAt)
+		for _, offset := range []int64{500000, 10, 1000000, 3} {
+			_, err = seeker.Seek(offset, io.SeekStart)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]byte, 100)
+			_, err = io.ReadFull(seeker, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want[offset:offset+100]) {
+				t.Fatalf("noRange=%v: Read at %d returned wrong data", noRange, offset)
+			}
+			_, err = readerAt.ReadAt(got, offset+7)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want[offset+7:offset+107]) {
+				t.Fatalf("noRange=%v: ReadAt %d returned wrong data", noRange, offset+7)
+			}
+		}
+		end, err := seeker.Seek(-10, io.SeekEnd)
+		if err != nil || end != int64(len(want))-10 {
+			t.Fatalf("Seek: got %d, %v", end, err)
+		}
+		_ = file.Close()
+	}
+}
+
+// TestFileReadAtNoRange checks that random access to a file on a server
+// that ignores ranges downloads the file only once.
+func TestFileReadAtNoRange(t *testing.T) {
+	s := newTestServer(t)
+	s.SetNoRange(true)
+	f := newTestFS(t, s, "/share")
+	want := []byte(testFiles["/share/big.bin"])
+
+	fsFile, err := f.Open("big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	readerAt := fsFile.(io.ReaderAt)
+	got := make([]byte, 100)
+	for _, offset := range []int64{700000, 10, 1048000, 300000, 0, 1048500} {
+		n, err := readerAt.ReadAt(got, offset)
+		wantN := len(want) - int(offset)
+		if wantN > len(got) {
+			wantN = len(got)
+		}
+		if n != wantN || (err != nil && !(err == io.EOF && n < len(got))) {
+			t.Fatalf("ReadAt %d: got %d, %v", offset, n, err)
+		}
+		if !bytes.Equal(got[:n], want[offset:offset+int64(n)]) {
+			t.Fatalf("ReadAt %d returned wrong data", offset)
+		}
+	}
+	if n := s.Calls(downloadMethod); n != 1 {
+		t.Fatalf("got %d downloads, want 1", n)
+	}
+
+	spool := fsFile.(*file).spool.Name()
+	err = fsFile.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = os.Stat(spool)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("spool file %s left behind", spool)
+	}
+}
+
+func TestFileConcurrentReadAt(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	want := []byte(testFiles["/share/big.bin"])
+	file, err := f.Open("big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		got := make([]byte, 1000)
+		for i := 0; i < 20; i++ {
+			offset := int64(i * 50000)
+			_, err := file.(io.ReaderAt).ReadAt(got, offset)
+			if err != nil || !bytes.Equal(got, want[offset:offset+1000]) {
+				t.Errorf("ReadAt %d: %v", offset, err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		got, err := io.ReadAll(file)
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("ReadAll: %v", err)
+		}
+	}()
+	wg.Wait()
+	_ = file.Close()
+}
+
+func TestFileClosed(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	file, err := f.Open("hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := s.Calls("SYNO.FileStation.Download.download")
+	_, err = file.Read(make([]byte, 1))
+	if !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("Read: got %v, want %v", err, fs.ErrClosed)
+	}
+	_, err = file.(io.ReaderAt).ReadAt(make([]byte, 1), 0)
+	if !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("ReadAt: got %v, want %v", err, fs.ErrClosed)
+	}
+	if s.Calls("SYNO.FileStation.Download.download") != calls {
+		t.Error("read after close started a download")
+	}
+}
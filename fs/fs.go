@@ -1,29 +1,87 @@
 package fs
 
 import (
+	"bytes"
+	"errors"
 	"github.com/ngyewch/go-syno/api"
 	"github.com/ngyewch/go-syno/api/filestation"
 	"io"
 	"io/fs"
 	"path"
-	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
+var (
+	_ fs.FS         = (*FS)(nil)
+	_ fs.StatFS     = (*FS)(nil)
+	_ fs.ReadDirFS  = (*FS)(nil)
+	_ fs.ReadFileFS = (*FS)(nil)
+	_ fs.GlobFS     = (*FS)(nil)
+	_ fs.SubFS      = (*FS)(nil)
+)
+
+var (
+	fileAdditional = []filestation.AdditionalField{
+		filestation.AdditionalRealPath,
+		filestation.AdditionalSize,
+		filestation.AdditionalOwner,
+		filestation.AdditionalTime,
+		filestation.AdditionalPerm,
+		filestation.AdditionalType,
+	}
+	fileAdditionalMountPoint = []filestation.AdditionalField{
+		filestation.AdditionalRealPath,
+		filestation.AdditionalSize,
+		filestation.AdditionalOwner,
+		filestation.AdditionalTime,
+		filestation.AdditionalPerm,
+		filestation.AdditionalType,
+		filestation.AdditionalMountPointType,
+	}
+)
+
 type FS struct {
 	dir            string
 	client         *api.Client
 	fileStationApi *filestation.Api
+	info           *filestation.InfoResponse
+	cache          *metadataCache
+	contentCache   *contentCache
 }
 
+// NewFS returns an FS rooted at dir. If dir is "/", the top level directory
+// lists the shared folders.
 func NewFS(client *api.Client, dir string) (*FS, error) {
+	fileStationApi := filestation.New(client)
+	info, err := getInfo(fileStationApi)
+	if err != nil {
+		return nil, err
+	}
+	return newFS(client, fileStationApi, info, dir)
+}
+
+func newFS(client *api.Client, fileStationApi *filestation.Api, info *filestation.InfoResponse, dir string) (*FS, error) {
 	if dir == "" {
 		return nil, fs.ErrInvalid
 	}
-	fileStationApi := filestation.New(client)
+	if path.Clean(dir) == "/" {
+		dir = "/"
+	}
+	f := &FS{
+		dir:            dir,
+		client:         client,
+		fileStationApi: fileStationApi,
+		info:           info,
+	}
+	if f.isRoot() {
+		// the root directory is synthesized from the shared folders
+		return f, nil
+	}
 	getInfoResponse, err := fileStationApi.GetInfo(filestation.GetInfoRequest{
 		Path:       []string{dir},
-		Additional: []string{"size", "time"},
+		Additional: f.additional(),
 	})
 	if err != nil {
 		return nil, err
@@ -40,11 +98,86 @@ func NewFS(client *api.Client, dir string) (*FS, error) {
 		return nil, fs.ErrInvalid
 	}
 
-	return &FS{
-		dir:            dir,
-		client:         client,
-		fileStationApi: fileStationApi,
-	}, nil
+	return f, nil
+}
+
+func getInfo(fileStationApi *filestation.Api) (*filestation.InfoResponse, error) {
+	infoResponse, err := fileStationApi.Info()
+	if err != nil {
+		var apiError *api.Error
+		if errors.As(err, &apiError) && apiError.Code == 102 {
+			// SYNO.FileStation.Info is not available, assume no optional capabilities
+			return &filestation.InfoResponse{}, nil
+		}
+		return nil, err
+	}
+	if infoResponse.Data == nil {
+		return &filestation.InfoResponse{}, nil
+	}
+	return infoResponse.Data, nil
+}
+
+// Info returns the FileStation capabilities of the session backing f.
+func (f *FS) Info() *filestation.InfoResponse {
+	return f.info
+}
+
+// SupportsMountPoints reports whether the server can mount virtual folders
+// (CIFS, NFS, ISO), in which case entries report their mount point type.
+func (f *FS) SupportsMountPoints() bool {
+	return f.info != nil && len(f.info.SupportVirtualProtocol) > 0
+}
+
+// EnableCache caches Stat results and directory listings. Listings also
+// populate the Stat results of their entries. The cache is shared with FS
+// values subsequently returned by Sub.
+func (f *FS) EnableCache(options CacheOptions) {
+	f.cache = newMetadataCache(options)
+}
+
+// EnableContentCache caches file contents on local disk. Cached contents are
+// validated against the size and mtime reported by the server, bypassing the
+// metadata cache, before being served. Files that cannot be cached, e.g.
+// because the local disk is full, are read directly from the server. The
+// cache is shared with FS values subsequently returned by Sub.
+func (f *FS) EnableContentCache(options ContentCacheOptions) error {
+	contentCache, err := newContentCache(options)
+	if err != nil {
+		return err
+	}
+	f.contentCache = contentCache
+	return nil
+}
+
+// Invalidate drops cached metadata for name, everything below it, and the
+// listing of its parent directory, along with their cached contents.
+func (f *FS) Invalidate(name string) {
+	if !fs.ValidPath(name) {
+		return
+	}
+	if f.cache != nil {
+		f.cache.invalidate(f.resolvePath(name))
+	}
+	if f.contentCache != nil {
+		f.contentCache.invalidate(f.resolvePath(name))
+	}
+}
+
+// InvalidateAll drops all cached metadata and contents.
+func (f *FS) InvalidateAll() {
+	if f.cache != nil {
+		f.cache.invalidateAll()
+	}
+	if f.contentCache != nil {
+		f.contentCache.invalidateAll()
+	}
+}
+
+func (f *FS) additional() []filestation.AdditionalField {
+	if f.SupportsMountPoints() {
+		return fileAdditionalMountPoint
+	}
+	return fileAdditional
 }
 
 func (f *FS) resolvePath(name string) string {
@@ -52,24 +185,73 @@ func (f *FS) resolvePath(name string) string {
 }
 
 func (f *FS) Open(name string) (fs.File, error) {
-	r, err := f.fileStationApi.Download(filestation.DownloadRequest{
-		Path: []string{f.resolvePath(name)},
-		Mode: "download",
-	})
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	info, err := f.stat(name)
 	if err != nil {
-		return nil, err
+		return nil, pathError("open", name, err)
 	}
-	return &file{
-		fs:   f,
-		path: name,
-		r:    r,
-	}, nil
+	if info.file.IsDir {
+		return openDir(f, name, info), nil
+	}
+	if f.contentCache != nil && f.contentCache.accepts(info.Size()) {
+		file, err := f.openCached(name, info)
+		if err == nil {
+			return file, nil
+		}
+		// fall back to ranged reads if the content cannot be cached
+	}
+	return openFile(f, name, info.Size()), nil
 }
 
 func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	info, err := f.stat(name)
+	if err != nil {
+		return nil, pathError("stat", name, err)
+	}
+	return info, nil
+}
+
+func (f *FS) stat(name string) (*dirEntry, error) {
+	resolvedPath := f.resolvePath(name)
+	if f.isRoot() && resolvedPath == "/" {
+		return rootEntry(), nil
+	}
+	if f.isRoot() && path.Dir(resolvedPath) == "/" {
+		return f.statShare(resolvedPath)
+	}
+	var entry *dirEntry
+	if f.cache != nil {
+		file, ok := f.cache.getStat(resolvedPath)
+		if ok {
+			entry = &dirEntry{
+				file: file,
+			}
+		}
+	}
+	if entry == nil {
+		var err error
+		entry, err = f.statRemote(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// like os.DirFS, the root directory is followed if it is a symlink
+	entry.root = name == "."
+	return entry, nil
+}
+
+// statRemote stats name on the server, bypassing and refreshing the metadata
+// cache.
+func (f *FS) statRemote(name string) (*dirEntry, error) {
+	resolvedPath := f.resolvePath(name)
 	getInfoResponse, err := f.fileStationApi.GetInfo(filestation.GetInfoRequest{
-		Path:       []string{f.resolvePath(name)},
-		Additional: []string{"size", "time"},
+		Path:       []string{resolvedPath},
+		Additional: f.additional(),
 	})
 	if err != nil {
 		return nil, err
@@ -77,21 +259,66 @@ func (f *FS) Stat(name string) (fs.FileInfo, error) {
 	if getInfoResponse.Data == nil || len(getInfoResponse.Data.Files) == 0 || getInfoResponse.Data.Files[0].Additional == nil {
 		return nil, fs.ErrNotExist
 	}
+	file := getInfoResponse.Data.Files[0]
+	if f.cache != nil {
+		f.cache.putStat(resolvedPath, file)
+	}
 	return &dirEntry{
-		file: getInfoResponse.Data.Files[0],
+		file: file,
 	}, nil
 }
 
 func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
-	listResponse, err := f.fileStationApi.List(filestation.ListRequest{
-		FolderPath: f.resolvePath(name),
-		Additional: []string{"size", "time"},
-	})
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	dirEntries, err := f.readDir(name, nil)
 	if err != nil {
-		return nil, err
+		return nil, pathError("readdir", name, err)
 	}
-	var dirEntries []fs.DirEntry
-	for _, file := range listResponse.Data.Files {
+	sort.Slice(dirEntries, func(i, j int) bool {
+		return dirEntries[i].Name() < dirEntries[j].Name()
+	})
+	return dirEntries, nil
+}
+
+func (f *FS) readDir(name string, pattern []string) ([]fs.DirEntry, error) {
+	resolvedPath := f.resolvePath(name)
+	if f.isRoot() && resolvedPath == "/" {
+		shareEntries, err := f.shareEntries()
+		if err != nil {
+			return nil, err
+		}
+		dirEntries := make([]fs.DirEntry, 0, len(shareEntries))
+		for _, shareEntry := range shareEntries {
+			dirEntries = append(dirEntries, shareEntry)
+		}
+		return dirEntries, nil
+	}
+	var files []filestation.File
+	cached := false
+	if f.cache != nil && len(pattern) == 0 {
+		files, cached = f.cache.getListing(resolvedPath)
+	}
+	if !cached {
+		it := f.fileStationApi.ListIter(filestation.ListRequest{
+			FolderPath: resolvedPath,
+			Pattern:    pattern,
+			Additional: f.additional(),
+		})
+		for it.Next() {
+			files = append(files, it.Value())
+		}
+		if it.Err() != nil {
+			return nil, it.Err()
+		}
+		if f.cache != nil && len(pattern) == 0 {
+			f.cache.putListing(resolvedPath, files)
+		}
+	}
+
+	dirEntries := make([]fs.DirEntry, 0, len(files))
+	for _, file := range files {
 		dirEntries = append(dirEntries, &dirEntry{
 			file: file,
 		})
@@ -99,50 +326,171 @@ func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
 	return dirEntries, nil
 }
 
-func (f *FS) Sub(dir string) (fs.FS, error) {
-	return NewFS(f.client, f.resolvePath(dir))
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	info, err := f.stat(name)
+	if err != nil {
+		return nil, pathError("readfile", name, err)
+	}
+	if info.file.IsDir {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errIsDirectory}
+	}
+
+	var r io.ReadCloser
+	if f.contentCache != nil && f.contentCache.accepts(info.Size()) {
+		file, err := f.openCached(name, info)
+		if err == nil {
+			r = file
+		}
+	}
+	if r == nil {
+		r, err = f.fileStationApi.Download(filestation.DownloadRequest{
+			Path: []string{f.resolvePath(name)},
+			Mode: filestation.DownloadModeDownload,
+		})
+	}
+	if err != nil {
+		return nil, pathError("readfile", name, err)
+	}
+	defer func(r io.ReadCloser) {
+		_ = r.Close()
+	}(r)
+
+	var buf bytes.Buffer
+	if info.Size() > 0 {
+		buf.Grow(int(info.Size()))
+	}
+	_, err = buf.ReadFrom(r)
+	if err != nil {
+		return nil, pathError("readfile", name, err)
+	}
+	return buf.Bytes(), nil
 }
 
-// ----
+// Glob matches the last path element of pattern server-side via the List
+// pattern parameter, following the semantics of fs.Glob.
+func (f *FS) Glob(pattern string) ([]string, error) {
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return nil, err
+	}
+	if !hasMeta(pattern) {
+		if !fs.ValidPath(pattern) {
+			return nil, nil
+		}
+		_, err = f.stat(pattern)
+		if err != nil {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+
+	dir, file := path.Split(pattern)
+	dir = cleanGlobPath(dir)
+	if !hasMeta(dir) {
+		return f.glob(dir, file, nil)
+	}
+	if dir == pattern {
+		return nil, path.ErrBadPattern
+	}
+
+	dirMatches, err := f.Glob(dir)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, d := range dirMatches {
+		matches, err = f.glob(d, file, matches)
+		if err != nil {
+			return matches, err
+		}
+	}
+	return matches, nil
+}
 
-type file struct {
-	fs   *FS
-	path string
-	r    io.ReadCloser
+func (f *FS) glob(dir string, pattern string, matches []string) ([]string, error) {
+	if !fs.ValidPath(dir) {
+		return matches, nil
+	}
+	info, err := f.stat(dir)
+	if err != nil || !info.file.IsDir {
+		return matches, nil
+	}
+	var serverPattern []string
+	if !strings.ContainsAny(pattern, `[\`) {
+		serverPattern = []string{pattern}
+	}
+	dirEntries, err := f.readDir(dir, serverPattern)
+	if err != nil {
+		return matches, nil
+	}
+	sort.Slice(dirEntries, func(i, j int) bool {
+		return dirEntries[i].Name() < dirEntries[j].Name()
+	})
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return matches, err
+		}
+		if matched {
+			matches = append(matches, path.Join(dir, name))
+		}
+	}
+	return matches, nil
 }
 
-func (f *file) Close() error {
-	return f.r.Close()
+func hasMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[\`)
 }
 
-func (f *file) Read(p []byte) (int, error) {
-	return f.r.Read(p)
+func cleanGlobPath(path string) string {
+	switch path {
+	case "":
+		return "."
+	default:
+		return path[0 : len(path)-1]
+	}
 }
 
-func (f *file) Stat() (fs.FileInfo, error) {
-	return f.fs.Stat(f.path)
+func (f *FS) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+	if dir == "." {
+		return f, nil
+	}
+	sub, err := newFS(f.client, f.fileStationApi, f.info, f.resolvePath(dir))
+	if err != nil {
+		return nil, pathError("sub", dir, err)
+	}
+	sub.cache = f.cache
+	sub.contentCache = f.contentCache
+	return sub, nil
 }
 
 // ----
 
 type dirEntry struct {
-	file filestation.File
+	file  filestation.File
+	share *filestation.SharedFolder
+	root  bool
 }
 
 func (d *dirEntry) Name() string {
-	return filepath.Base(d.file.Path)
+	return path.Base(d.file.Path)
 }
 
+// IsDir reports whether the entry is a directory. Like os.Lstat, it is false
+// for symlinks to directories.
 func (d *dirEntry) IsDir() bool {
-	return d.file.IsDir
+	return d.Mode().IsDir()
 }
 
 func (d *dirEntry) Type() fs.FileMode {
-	if d.file.IsDir {
-		return fs.ModeDir
-	} else {
-		return 0
-	}
+	return d.Mode().Type()
 }
 
 func (d *dirEntry) Info() (fs.FileInfo, error) {
@@ -157,7 +505,13 @@ func (d *dirEntry) Size() int64 {
 }
 
 func (d *dirEntry) Mode() fs.FileMode {
-	return d.Type()
+	mode := d.perm()
+	if d.isSymlink() {
+		mode |= fs.ModeSymlink
+	} else if d.file.IsDir {
+		mode |= fs.ModeDir
+	}
+	return mode
 }
 
 func (d *dirEntry) ModTime() time.Time {
@@ -167,6 +521,21 @@ func (d *dirEntry) ModTime() time.Time {
 	return time.Unix(d.file.Additional.Time.Mtime, 0)
 }
 
+// Sys returns a *Sys.
 func (d *dirEntry) Sys() any {
-	return d.file
+	return d.sys()
+}
+
+// MountPointType returns the mount point type (e.g. "remote", "iso") of a
+// FileInfo or DirEntry obtained from FS, or "" if it is not a mount point.
+func MountPointType(fi any) string {
+	d, ok := fi.(*dirEntry)
+	if !ok || d.file.Additional == nil {
+		return ""
+	}
+	return d.file.Additional.MountPointType
+}
+
+func IsMountPoint(fi any) bool {
+	return MountPointType(fi) != ""
 }
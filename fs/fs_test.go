@@ -0,0 +1,251 @@
+package fs
+
+import (
+	"errors"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/internal/fakedsm"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var testFiles = map[string]string{
+	"/share/hello.txt":        "hello, world\n",
+	"/share/big.bin":          strings.Repeat("0123456789abcdef", 64*1024),
+	"/share/dir/a.go":         "package a\n",
+	"/share/dir/b.txt":        "bbbb",
+	"/share/dir/sub/c.md":     "# c\n",
+	"/share/dir/sub/deep/d.x": "d",
+	"/share/empty/":           "",
+	"/other/x.txt":            "x",
+}
+
+var testFileNames = []string{
+	"hello.txt",
+	"big.bin",
+	"dir/a.go",
+	"dir/b.txt",
+	"dir/sub/c.md",
+	"dir/sub/deep/d.x",
+	"empty",
+}
+
+func newTestServer(t *testing.T) *fakedsm.Server {
+	s := fakedsm.New(testFiles)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestFS(t *testing.T, s *fakedsm.Server, dir string) *FS {
+	client, err := api.NewClient(s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewFS(client, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestFS(t *testing.T) {
+	s := newTestServer(t)
+	err := fstest.TestFS(newTestFS(t, s, "/share"), testFileNames...)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSPaged(t *testing.T) {
+	s := newTestServer(t)
+	s.SetPageSize(2)
+	err := fstest.TestFS(newTestFS(t, s, "/share"), testFileNames...)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSNoRange(t *testing.T) {
+	s := newTestServer(t)
+	s.SetNoRange(true)
+	err := fstest.TestFS(newTestFS(t, s, "/share"), testFileNames...)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSSub(t *testing.T) {
+	s := newTestServer(t)
+	sub, err := fs.Sub(newTestFS(t, s, "/share"), "dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fstest.TestFS(sub, "a.go", "b.txt", "sub/c.md", "sub/deep/d.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRootFS(t *testing.T) {
+	s := newTestServer(t)
+	err := fstest.TestFS(newTestFS(t, s, "/"), "share/hello.txt", "share/dir/sub/c.md", "other/x.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewFSWithoutInfo(t *testing.T) {
+	s := newTestServer(t)
+	s.SetInfo(nil)
+	f := newTestFS(t, s, "/share")
+	if f.SupportsMountPoints() {
+		t.Fatal("expected no mount point support")
+	}
+	_, err := f.Stat("hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewFSSessionError(t *testing.T) {
+	s := newTestServer(t)
+	s.SetSessionError(119)
+	client, err := api.NewClient(s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"/", "/share"} {
+		_, err = NewFS(client, dir)
+		var apiError *api.Error
+		if !errors.As(err, &apiError) || apiError.Code != 119 {
+			t.Fatalf("%s: got %v, want error 119", dir, err)
+		}
+	}
+}
+
+func TestNewFSVirtualProtocols(t *testing.T) {
+	for _, protocols := range []any{[]string{"cifs", "nfs", "iso"}, "cifs,nfs,iso"} {
+		s := newTestServer(t)
+		s.SetInfo(map[string]any{
+			"hostname":                 "fakedsm",
+			"support_virtual_protocol": protocols,
+			"uid":                      1024,
+			"gid":                      100,
+		})
+		f := newTestFS(t, s, "/share")
+		if !f.SupportsMountPoints() || len(f.Info().VirtualProtocols()) != 3 {
+			t.Fatalf("%v: %v", protocols, f.Info().VirtualProtocols())
+		}
+		if f.Info().Uid != 1024 || f.Info().Gid != 100 {
+			t.Fatalf("got uid %d, gid %d", f.Info().Uid, f.Info().Gid)
+		}
+	}
+}
+
+func TestNewFSErrors(t *testing.T) {
+	s := newTestServer(t)
+	client, err := api.NewClient(s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewFS(client, "/missing")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("got %v, want %v", err, fs.ErrNotExist)
+	}
+	_, err = NewFS(client, "/share/hello.txt")
+	if !errors.Is(err, fs.ErrInvalid) {
+		t.Fatalf("got %v, want %v", err, fs.ErrInvalid)
+	}
+}
+
+func TestInvalidPath(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share/dir")
+	for _, name := range []string{"../hello.txt", "/share/hello.txt", "sub/../a.go", "sub/", ""} {
+		_, err := f.Open(name)
+		if !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Open(%q): got %v, want %v", name, err, fs.ErrInvalid)
+		}
+		_, err = f.Stat(name)
+		if !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Stat(%q): got %v, want %v", name, err, fs.ErrInvalid)
+		}
+		_, err = f.ReadDir(name)
+		if !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("ReadDir(%q): got %v, want %v", name, err, fs.ErrInvalid)
+		}
+		_, err = f.ReadFile(name)
+		if !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("ReadFile(%q): got %v, want %v", name, err, fs.ErrInvalid)
+		}
+		_, err = f.Sub(name)
+		if !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Sub(%q): got %v, want %v", name, err, fs.ErrInvalid)
+		}
+	}
+	if s.Calls("SYNO.FileStation.Download.download") != 0 {
+		t.Error("invalid paths reached the server")
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	s := newTestServer(t)
+	s.SetError("/share/dir/sub", 105)
+	s.SetError("/share/empty", 407)
+	f := newTestFS(t, s, "/share")
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"missing", fs.ErrNotExist},
+		{"dir/missing.txt", fs.ErrNotExist},
+		{"dir/sub", fs.ErrPermission},
+		{"dir/sub/c.md", fs.ErrPermission},
+		{"empty", fs.ErrPermission},
+	}
+	for _, test := range tests {
+		_, err := f.Stat(test.name)
+		var pathErr *fs.PathError
+		if !errors.Is(err, test.want) || !errors.As(err, &pathErr) || pathErr.Path != test.name {
+			t.Errorf("Stat(%q): got %v, want %v", test.name, err, test.want)
+		}
+		_, err = f.Open(test.name)
+		if !errors.Is(err, test.want) {
+			t.Errorf("Open(%q): got %v, want %v", test.name, err, test.want)
+		}
+		_, err = f.ReadDir(test.name)
+		if !errors.Is(err, test.want) {
+			t.Errorf("ReadDir(%q): got %v, want %v", test.name, err, test.want)
+		}
+	}
+}
+
+func TestGlob(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestFS(t, s, "/share")
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*.txt", []string{"hello.txt"}},
+		{"dir/*", []string{"dir/a.go", "dir/b.txt", "dir/sub"}},
+		{"*/*/c.md", []string{"dir/sub/c.md"}},
+		{"dir/[ab].*", []string{"dir/a.go", "dir/b.txt"}},
+		{"nope/*", nil},
+	}
+	for _, test := range tests {
+		matches, err := f.Glob(test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(matches, ",") != strings.Join(test.want, ",") {
+			t.Errorf("Glob(%q): got %v, want %v", test.pattern, matches, test.want)
+		}
+	}
+	_, err := f.Glob("[")
+	if err == nil {
+		t.Error("expected bad pattern error")
+	}
+}
@@ -0,0 +1,112 @@
+package fs
+
+import (
+	"github.com/ngyewch/go-syno/api/filestation"
+	"io/fs"
+	"path"
+	"strconv"
+)
+
+// Sys is the value returned by the Sys method of FileInfo and DirEntry values
+// obtained from FS.
+type Sys struct {
+	File filestation.File
+	// Share is set for shared folders listed by a root FS.
+	Share *filestation.SharedFolder
+	Uid   int
+	Gid   int
+	User  string
+	Group string
+	// Posix holds the POSIX permission bits as reported by the server.
+	Posix fs.FileMode
+	// IsAclMode reports whether access is governed by Windows ACLs rather
+	// than the POSIX bits, in which case Acl holds the session user's rights.
+	IsAclMode bool
+	Acl       *filestation.Acl
+	Symlink   bool
+	// RealPath is the path on the volume, which for a symlink is the path of
+	// its target.
+	RealPath string
+}
+
+// posixMode converts the server's representation of permission bits, where
+// e.g. 755 means rwxr-xr-x, to a FileMode.
+func posixMode(posix int) fs.FileMode {
+	mode, err := strconv.ParseUint(strconv.Itoa(posix), 8, 32)
+	if err != nil {
+		return 0
+	}
+	return fs.FileMode(mode) & fs.ModePerm
+}
+
+// aclMode approximates ACL rights of the session user as owner permission
+// bits.
+func aclMode(acl *filestation.Acl) fs.FileMode {
+	var mode fs.FileMode
+	if acl.Read {
+		mode |= 0o400
+	}
+	if acl.Write || acl.Append || acl.Del {
+		mode |= 0o200
+	}
+	if acl.Exec {
+		mode |= 0o100
+	}
+	return mode
+}
+
+func (d *dirEntry) perm() fs.FileMode {
+	if d.file.Additional == nil || d.file.Additional.Perm == nil {
+		return 0
+	}
+	perm := d.file.Additional.Perm
+	if perm.IsAclMode && perm.Acl != nil {
+		return aclMode(perm.Acl)
+	}
+	return posixMode(perm.Posix)
+}
+
+// isSymlink reports whether the entry is a symbolic link. FileStation does not
+// flag symlinks, but reports the resolved path as real_path, so a link is
+// detected when its name differs from that of its target. Shared folders are
+// never reported as links, since their real path may legitimately differ,
+// e.g. /home resolves to /volume1/homes/<user>. Links to a target of the same
+// name are not detected.
+func (d *dirEntry) isSymlink() bool {
+	if d.root || d.share != nil || d.file.Additional == nil {
+		return false
+	}
+	if path.Dir(d.file.Path) == "/" {
+		return false
+	}
+	additional := d.file.Additional
+	if additional.RealPath == "" || additional.MountPointType != "" {
+		return false
+	}
+	return path.Base(additional.RealPath) != path.Base(d.file.Path)
+}
+
+func (d *dirEntry) sys() *Sys {
+	sys := &Sys{
+		File:    d.file,
+		Share:   d.share,
+		Symlink: d.isSymlink(),
+	}
+	additional := d.file.Additional
+	if additional == nil {
+		return sys
+	}
+	sys.RealPath = additional.RealPath
+	if additional.Owner != nil {
+		sys.Uid = additional.Owner.Uid
+		sys.Gid = additional.Owner.Gid
+		sys.User = additional.Owner.User
+		sys.Group = additional.Owner.Group
+	}
+	if additional.Perm != nil {
+		sys.Posix = posixMode(additional.Perm.Posix)
+		sys.IsAclMode = additional.Perm.IsAclMode
+		sys.Acl = additional.Perm.Acl
+	}
+	return sys
+}
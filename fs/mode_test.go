@@ -0,0 +1,95 @@
+package fs
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestModeShareRoot(t *testing.T) {
+	s := newTestServer(t)
+	s.SetRealPath("/share", "/volume1/homes/admin")
+
+	for _, dir := range []string{"/share", "/"} {
+		f := newTestFS(t, s, dir)
+		name := "."
+		if dir == "/" {
+			name = "share"
+		}
+		fileInfo, err := f.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fileInfo.Mode().Type() != fs.ModeDir {
+			t.Fatalf("%s: got mode %v, want a directory", dir, fileInfo.Mode())
+		}
+	}
+
+	var paths []string
+	err := fs.WalkDir(newTestFS(t, s, "/share"), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(paths)
+	want := "big.bin,dir/a.go,dir/b.txt,dir/sub/c.md,dir/sub/deep/d.x,hello.txt"
+	if strings.Join(paths, ",") != want {
+		t.Fatalf("got %v, want %s", paths, want)
+	}
+}
+
+func TestModeSymlink(t *testing.T) {
+	s := newTestServer(t)
+	s.Mkdir("/share/link")
+	s.SetRealPath("/share/link", "/volume1/share/dir")
+	s.SetRealPath("/share/dir/sub", "/volume1/other/sub")
+
+	f := newTestFS(t, s, "/share")
+	tests := []struct {
+		name string
+		want fs.FileMode
+	}{
+		{"link", fs.ModeSymlink},
+		{"dir", fs.ModeDir},
+		// a link to a target of the same name is not detected
+		{"dir/sub", fs.ModeDir},
+		{"hello.txt", 0},
+	}
+	for _, test := range tests {
+		fileInfo, err := f.Stat(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fileInfo.Mode().Type() != test.want {
+			t.Errorf("%s: got type %v, want %v", test.name, fileInfo.Mode().Type(), test.want)
+		}
+		if fileInfo.Mode().Perm() != 0o755 {
+			t.Errorf("%s: got perm %v, want %v", test.name, fileInfo.Mode().Perm(), fs.FileMode(0o755))
+		}
+		sys := fileInfo.Sys().(*Sys)
+		if sys.Symlink != (test.want == fs.ModeSymlink) {
+			t.Errorf("%s: got Symlink %v", test.name, sys.Symlink)
+		}
+	}
+
+	// the root of an FS is followed
+	sub, err := fs.Sub(f, "link")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileInfo, err := fs.Stat(sub, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fileInfo.IsDir() {
+		t.Fatalf("got mode %v, want a directory", fileInfo.Mode())
+	}
+}
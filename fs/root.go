@@ -0,0 +1,127 @@
+package fs
+
+import (
+	"github.com/ngyewch/go-syno/api/filestation"
+	"io/fs"
+)
+
+var (
+	shareAdditional = []filestation.AdditionalField{
+		filestation.AdditionalRealPath,
+		filestation.AdditionalOwner,
+		filestation.AdditionalTime,
+		filestation.AdditionalPerm,
+		filestation.AdditionalVolumeStatus,
+	}
+	shareAdditionalMountPoint = []filestation.AdditionalField{
+		filestation.AdditionalRealPath,
+		filestation.AdditionalOwner,
+		filestation.AdditionalTime,
+		filestation.AdditionalPerm,
+		filestation.AdditionalVolumeStatus,
+		filestation.AdditionalMountPointType,
+	}
+)
+
+// isRoot reports whether f is rooted at "/", in which case the top level is
+// synthesized from the shared folders.
+func (f *FS) isRoot() bool {
+	return f.dir == "/"
+}
+
+func (f *FS) shareAdditional() []filestation.AdditionalField {
+	if f.SupportsMountPoints() {
+		return shareAdditionalMountPoint
+	}
+	return shareAdditional
+}
+
+func (f *FS) shares() ([]filestation.SharedFolder, error) {
+	if f.cache != nil {
+		shares, ok := f.cache.getShares()
+		if ok {
+			return shares, nil
+		}
+	}
+	it := f.fileStationApi.ListShareIter(filestation.ListShareRequest{
+		Additional: f.shareAdditional(),
+	})
+	var shares []filestation.SharedFolder
+	for it.Next() {
+		shares = append(shares, it.Value())
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	if f.cache != nil {
+		f.cache.putShares(shares)
+	}
+	return shares, nil
+}
+
+func (f *FS) shareEntries() ([]*dirEntry, error) {
+	shares, err := f.shares()
+	if err != nil {
+		return nil, err
+	}
+	shareEntries := make([]*dirEntry, 0, len(shares))
+	for _, share := range shares {
+		shareEntries = append(shareEntries, newShareEntry(share))
+	}
+	return shareEntries, nil
+}
+
+func (f *FS) statShare(resolvedPath string) (*dirEntry, error) {
+	shares, err := f.shares()
+	if err != nil {
+		return nil, err
+	}
+	for _, share := range shares {
+		if share.Path == resolvedPath {
+			return newShareEntry(share), nil
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+func rootEntry() *dirEntry {
+	return &dirEntry{
+		file: filestation.File{
+			Path:  "/",
+			Name:  "/",
+			IsDir: true,
+			Additional: &filestation.FileAdditional{
+				Perm: &filestation.Permissions{
+					Posix: 555,
+				},
+			},
+		},
+	}
+}
+
+func newShareEntry(share filestation.SharedFolder) *dirEntry {
+	file := filestation.File{
+		Path:       share.Path,
+		Name:       share.Name,
+		IsDir:      true,
+		Additional: &filestation.FileAdditional{},
+	}
+	if share.Additional != nil {
+		file.Additional.RealPath = share.Additional.RealPath
+		file.Additional.Owner = share.Additional.Owner
+		file.Additional.Time = share.Additional.Time
+		file.Additional.MountPointType = share.Additional.MountPointType
+		file.Additional.VolumeStatus = share.Additional.VolumeStatus
+		if share.Additional.Perm != nil {
+			file.Additional.Perm = &filestation.Permissions{
+				Posix:     share.Additional.Perm.Posix,
+				IsAclMode: share.Additional.Perm.IsAclMode,
+				Acl:       share.Additional.Perm.Acl,
+			}
+		}
+	}
+	return &dirEntry{
+		file:  file,
+		share: &share,
+	}
+}
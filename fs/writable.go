@@ -0,0 +1,423 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	chtimesTempPrefix = ".chtimes-"
+	renameTempPrefix  = ".rename-"
+)
+
+var (
+	errWriteOnly       = errors.New("file is write-only")
+	errChtimesDir      = errors.New("chtimes is not supported on directories")
+	errUploadCompleted = errors.New("upload already completed")
+	errUploadAborted   = errors.New("upload aborted")
+)
+
+// WritableFS extends FS with write operations backed by the FileStation
+// upload, create folder, delete, rename and copy/move APIs.
+type WritableFS struct {
+	*FS
+}
+
+func NewWritableFS(client *api.Client, dir string) (*WritableFS, error) {
+	f, err := NewFS(client, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &WritableFS{
+		FS: f,
+	}, nil
+}
+
+func (f *WritableFS) Sub(dir string) (fs.FS, error) {
+	sub, err := f.FS.Sub(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &WritableFS{
+		FS: sub.(*FS),
+	}, nil
+}
+
+// Create creates or truncates the named file. The content is streamed to the
+// server as it is written and the upload completes on Close. Use
+// CloseWithError to abandon the upload without replacing an existing file.
+func (f *WritableFS) Create(name string) (*WritableFile, error) {
+	return f.create(name, time.Time{})
+}
+
+func (f *WritableFS) create(name string, mtime time.Time) (*WritableFile, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrInvalid}
+	}
+	resolvedPath := f.resolvePath(name)
+
+	pr, pw := io.Pipe()
+	w := &WritableFile{
+		fs:   f,
+		name: name,
+		pw:   pw,
+		done: make(chan error, 1),
+	}
+	go func() {
+		_, err := f.fileStationApi.Upload(filestation.UploadRequest{
+			Path:      path.Dir(resolvedPath),
+			FileName:  path.Base(resolvedPath),
+			Reader:    pr,
+			Overwrite: filestation.OverwriteOverwrite,
+			Mtime:     mtime,
+		})
+		if err != nil {
+			err = pathError("create", name, err)
+			_ = pr.CloseWithError(err)
+		} else {
+			_ = pr.CloseWithError(errUploadCompleted)
+		}
+		f.Invalidate(name)
+		w.done <- err
+	}()
+	return w, nil
+}
+
+func (f *WritableFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	w, err := f.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	if err != nil {
+		_ = w.CloseWithError(err)
+		return err
+	}
+	return w.Close()
+}
+
+func (f *WritableFS) Mkdir(name string, perm fs.FileMode) error {
+	return f.mkdir("mkdir", name, false)
+}
+
+func (f *WritableFS) MkdirAll(name string, perm fs.FileMode) error {
+	if name == "." {
+		return nil
+	}
+	return f.mkdir("mkdirall", name, true)
+}
+
+func (f *WritableFS) mkdir(op string, name string, forceParent bool) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	resolvedPath := f.resolvePath(name)
+	_, err := f.fileStationApi.CreateFolder(filestation.CreateFolderRequest{
+		FolderPath:  []string{path.Dir(resolvedPath)},
+		Name:        []string{path.Base(resolvedPath)},
+		ForceParent: forceParent,
+	})
+	if forceParent {
+		// parents may have been created as well
+		f.Invalidate(strings.SplitN(name, "/", 2)[0])
+	} else {
+		f.Invalidate(name)
+	}
+	if err != nil {
+		return pathError(op, name, err)
+	}
+	return nil
+}
+
+// Remove removes the named file or empty directory.
+func (f *WritableFS) Remove(name string) error {
+	return f.remove("remove", name, false)
+}
+
+// RemoveAll removes name and any children it contains. It returns nil if
+// name does not exist.
+func (f *WritableFS) RemoveAll(name string) error {
+	err := f.remove("removeall", name, true)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (f *WritableFS) remove(op string, name string, recursive bool) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	_, err := f.stat(name)
+	if err != nil {
+		return pathError(op, name, err)
+	}
+	_, err = f.fileStationApi.Delete(filestation.DeleteRequest{
+		Path:      []string{f.resolvePath(name)},
+		Recursive: recursive,
+	})
+	f.Invalidate(name)
+	if err != nil {
+		return pathError(op, name, err)
+	}
+	return nil
+}
+
+// Rename renames oldname to newname, replacing newname if it is an existing
+// file, like os.Rename. Renames that move the file to another directory or
+// replace a file go through a temporary folder next to newname, so that a
+// failure part way leaves oldname in place.
+func (f *WritableFS) Rename(oldname string, newname string) error {
+	if !fs.ValidPath(oldname) || oldname == "." {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: fs.ErrInvalid}
+	}
+	if !fs.ValidPath(newname) || newname == "." {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: fs.ErrInvalid}
+	}
+	oldPath := f.resolvePath(oldname)
+	newPath := f.resolvePath(newname)
+	if oldPath == newPath {
+		return nil
+	}
+	defer func() {
+		f.Invalidate(oldname)
+		f.Invalidate(newname)
+	}()
+
+	if path.Dir(oldPath) == path.Dir(newPath) {
+		_, err := f.fileStationApi.Rename(filestation.RenameRequest{
+			Path: []string{oldPath},
+			Name: []string{path.Base(newPath)},
+		})
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(mapError(err), fs.ErrExist) {
+			return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: mapError(err)}
+		}
+	}
+	err := f.replace(oldname, newname)
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: mapError(err)}
+	}
+	return nil
+}
+
+// replace moves oldname into a temporary folder in the directory of newname,
+// renames it there and moves it over newname. If a step fails, the file is
+// moved back to oldname.
+func (f *WritableFS) replace(oldname string, newname string) error {
+	oldPath := f.resolvePath(oldname)
+	newPath := f.resolvePath(newname)
+	oldInfo, err := f.statRemote(oldname)
+	if err != nil {
+		return err
+	}
+	newInfo, err := f.statRemote(newname)
+	if err == nil && (newInfo.file.IsDir || oldInfo.file.IsDir) {
+		// only files are replaced
+		return fs.ErrExist
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	tempName := fmt.Sprintf("%s%s-%d", renameTempPrefix, path.Base(newPath), time.Now().UnixNano())
+	_, err = f.fileStationApi.CreateFolder(filestation.CreateFolderRequest{
+		FolderPath: []string{path.Dir(newPath)},
+		Name:       []string{tempName},
+	})
+	if err != nil {
+		return err
+	}
+	tempPath := path.Join(path.Dir(newPath), tempName)
+	defer func() {
+		// not recursive, so that a file stranded by a failed rollback is kept
+		_, _ = f.fileStationApi.Delete(filestation.DeleteRequest{
+			Path: []string{tempPath},
+		})
+	}()
+
+	err = f.move(oldPath, tempPath, false)
+	if err != nil {
+		return err
+	}
+	currentPath := path.Join(tempPath, path.Base(oldPath))
+	if path.Base(oldPath) != path.Base(newPath) {
+		_, err = f.fileStationApi.Rename(filestation.RenameRequest{
+			Path: []string{currentPath},
+			Name: []string{path.Base(newPath)},
+		})
+		if err != nil {
+			_ = f.move(currentPath, path.Dir(oldPath), false)
+			return err
+		}
+		currentPath = path.Join(tempPath, path.Base(newPath))
+	}
+	err = f.move(currentPath, path.Dir(newPath), true)
+	if err != nil {
+		if currentPath != path.Join(tempPath, path.Base(oldPath)) {
+			_, _ = f.fileStationApi.Rename(filestation.RenameRequest{
+				Path: []string{currentPath},
+				Name: []string{path.Base(oldPath)},
+			})
+		}
+		_ = f.move(path.Join(tempPath, path.Base(oldPath)), path.Dir(oldPath), false)
+		return err
+	}
+	return nil
+}
+
+// move moves the resolved path p into the directory destFolderPath and waits
+// for the server to complete the move.
+func (f *WritableFS) move(p string, destFolderPath string, overwrite bool) error {
+	req := filestation.CopyMoveRequest{
+		Path:           []string{p},
+		DestFolderPath: destFolderPath,
+		RemoveSrc:      true,
+	}
+	if overwrite {
+		req.Overwrite = filestation.OverwriteOverwrite
+	}
+	task, err := f.fileStationApi.CopyMove(req)
+	if err != nil {
+		return err
+	}
+	_, err = task.Wait(context.Background())
+	return err
+}
+
+// Chtimes changes the modification time of the named file; atime is ignored.
+// FileStation can only set times on upload, so the content is downloaded and
+// uploaded again, which is expensive for large files. The new copy is
+// uploaded into a temporary folder next to the file and moved over the
+// original only once complete, so a failed transfer leaves the original
+// untouched.
+func (f *WritableFS) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: fs.ErrInvalid}
+	}
+	info, err := f.stat(name)
+	if err != nil {
+		return pathError("chtimes", name, err)
+	}
+	if info.file.IsDir {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: errChtimesDir}
+	}
+	if info.ModTime().Equal(mtime.Truncate(time.Second)) {
+		return nil
+	}
+	defer f.Invalidate(name)
+
+	resolvedPath := f.resolvePath(name)
+	tempName := fmt.Sprintf("%s%s-%d", chtimesTempPrefix, path.Base(resolvedPath), time.Now().UnixNano())
+	_, err = f.fileStationApi.CreateFolder(filestation.CreateFolderRequest{
+		FolderPath: []string{path.Dir(resolvedPath)},
+		Name:       []string{tempName},
+	})
+	if err != nil {
+		return pathError("chtimes", name, err)
+	}
+	tempPath := path.Join(path.Dir(resolvedPath), tempName)
+	defer func() {
+		_, _ = f.fileStationApi.Delete(filestation.DeleteRequest{
+			Path:      []string{tempPath},
+			Recursive: true,
+		})
+	}()
+
+	r, err := f.fileStationApi.Download(filestation.DownloadRequest{
+		Path: []string{resolvedPath},
+		Mode: filestation.DownloadModeDownload,
+	})
+	if err != nil {
+		return pathError("chtimes", name, err)
+	}
+	defer func(r io.ReadCloser) {
+		_ = r.Close()
+	}(r)
+
+	w, err := f.create(path.Join(path.Dir(name), tempName, path.Base(name)), mtime)
+	if err != nil {
+		return pathError("chtimes", name, err)
+	}
+	_, err = io.Copy(w, r)
+	if err != nil {
+		_ = w.CloseWithError(err)
+		return pathError("chtimes", name, err)
+	}
+	err = w.Close()
+	if err != nil {
+		return pathError("chtimes", name, err)
+	}
+
+	err = f.move(path.Join(tempPath, path.Base(resolvedPath)), path.Dir(resolvedPath), true)
+	if err != nil {
+		return pathError("chtimes", name, err)
+	}
+	return nil
+}
+
+// ----
+
+type WritableFile struct {
+	fs     *WritableFS
+	name   string
+	pw     *io.PipeWriter
+	done   chan error
+	err    error
+	closed bool
+}
+
+func (w *WritableFile) Name() string {
+	return w.name
+}
+
+func (w *WritableFile) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, &fs.PathError{Op: "write", Path: w.name, Err: fs.ErrClosed}
+	}
+	return w.pw.Write(p)
+}
+
+func (w *WritableFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: w.name, Err: errWriteOnly}
+}
+
+// Close completes the upload and returns any error reported by the server.
+func (w *WritableFile) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	_ = w.pw.Close()
+	w.err = <-w.done
+	return w.err
+}
+
+// CloseWithError aborts the upload. The request body fails with err, so the
+// server discards what was written and an existing file is left untouched.
+func (w *WritableFile) CloseWithError(err error) error {
+	if w.closed {
+		return w.err
+	}
+	if err == nil {
+		err = errUploadAborted
+	}
+	w.closed = true
+	_ = w.pw.CloseWithError(err)
+	<-w.done
+	return nil
+}
+
+func (w *WritableFile) Stat() (fs.FileInfo, error) {
+	return w.fs.Stat(w.name)
+}
@@ -0,0 +1,319 @@
+package fs
+
+import (
+	"errors"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/internal/fakedsm"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestWritableFS(t *testing.T, s *fakedsm.Server, dir string) *WritableFS {
+	client, err := api.NewClient(s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewWritableFS(client, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func assertContent(t *testing.T, s *fakedsm.Server, p string, want string) {
+	t.Helper()
+	got, ok := s.ReadFile(p)
+	if !ok {
+		t.Fatalf("%s does not exist", p)
+	}
+	if got != want {
+		t.Fatalf("%s: got %d bytes, want %d bytes", p, len(got), len(want))
+	}
+}
+
+func assertNoTempFolders(t *testing.T, f *WritableFS, dir string) {
+	t.Helper()
+	dirEntries, err := f.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dirEntry := range dirEntries {
+		if strings.HasPrefix(dirEntry.Name(), chtimesTempPrefix) || strings.HasPrefix(dirEntry.Name(), renameTempPrefix) {
+			t.Fatalf("temporary folder %s left behind", dirEntry.Name())
+		}
+	}
+}
+
+func TestWritableFSCreate(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestWritableFS(t, s, "/share")
+
+	err := f.WriteFile("new.txt", []byte("new"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, s, "/share/new.txt", "new")
+
+	w, err := f.Create("hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		_, err = w.Write([]byte("chunk"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, s, "/share/hello.txt", "chunkchunkchunk")
+
+	w, err = f.Create("missing/x.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("got %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestWritableFSCloseWithError(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestWritableFS(t, s, "/share")
+
+	w, err := f.Create("big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.Write([]byte(strings.Repeat("x", 100000)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.CloseWithError(errors.New("source failed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, s, "/share/big.bin", testFiles["/share/big.bin"])
+
+	_, err = w.Write([]byte("more"))
+	if !errors.Is(err, fs.ErrClosed) {
+		t.Fatalf("got %v, want %v", err, fs.ErrClosed)
+	}
+}
+
+func TestWritableFSChtimes(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestWritableFS(t, s, "/share")
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	err := f.Chtimes("dir/a.go", time.Time{}, mtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, s, "/share/dir/a.go", testFiles["/share/dir/a.go"])
+	got, _ := s.Mtime("/share/dir/a.go")
+	if !got.Equal(mtime) {
+		t.Fatalf("got mtime %v, want %v", got, mtime)
+	}
+	assertNoTempFolders(t, f, "dir")
+
+	uploads := s.Calls("SYNO.FileStation.Upload.upload")
+	err = f.Chtimes("dir/a.go", time.Time{}, mtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Calls("SYNO.FileStation.Upload.upload") != uploads {
+		t.Fatal("unchanged mtime was uploaded again")
+	}
+
+	err = f.Chtimes("dir", time.Time{}, mtime)
+	if err == nil {
+		t.Fatal("expected error for directory")
+	}
+}
+
+func TestWritableFSChtimesInterrupted(t *testing.T) {
+	s := newTestServer(t)
+	s.SetTruncate("/share/big.bin", 500000)
+	f := newTestWritableFS(t, s, "/share")
+
+	err := f.Chtimes("big.bin", time.Time{}, time.Now())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	assertContent(t, s, "/share/big.bin", testFiles["/share/big.bin"])
+	got, _ := s.Mtime("/share/big.bin")
+	if !got.Equal(fakedsm.DefaultMtime) {
+		t.Fatalf("mtime changed to %v", got)
+	}
+	assertNoTempFolders(t, f, ".")
+}
+
+func TestWritableFSMkdirRemoveRename(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestWritableFS(t, s, "/share")
+
+	err := f.Mkdir("new", 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.Mkdir("new", 0755)
+	if !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("got %v, want %v", err, fs.ErrExist)
+	}
+	err = f.MkdirAll("a/b/c", 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Exists("/share/a/b/c") {
+		t.Fatal("MkdirAll did not create a/b/c")
+	}
+
+	err = f.Rename("hello.txt", "new/renamed.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, s, "/share/new/renamed.txt", testFiles["/share/hello.txt"])
+	if s.Exists("/share/hello.txt") {
+		t.Fatal("rename left the source behind")
+	}
+
+	err = f.Remove("dir")
+	if err == nil {
+		t.Fatal("expected error removing non-empty directory")
+	}
+	err = f.RemoveAll("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Exists("/share/dir/sub/c.md") {
+		t.Fatal("RemoveAll left children behind")
+	}
+	err = f.RemoveAll("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.Remove("dir")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("got %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestWritableFSRename(t *testing.T) {
+	s := newTestServer(t)
+	f := newTestWritableFS(t, s, "/share")
+	s.WriteFile("/share/dir/hello.txt", "other")
+
+	// the old name is taken in the new directory
+	err := f.Rename("hello.txt", "dir/renamed.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, s, "/share/dir/renamed.txt", testFiles["/share/hello.txt"])
+	assertContent(t, s, "/share/dir/hello.txt", "other")
+	if s.Exists("/share/hello.txt") {
+		t.Fatal("rename left the source behind")
+	}
+
+	// replace a file in the same directory
+	err = f.Rename("dir/renamed.txt", "dir/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, s, "/share/dir/b.txt", testFiles["/share/hello.txt"])
+	if s.Exists("/share/dir/renamed.txt") {
+		t.Fatal("rename left the source behind")
+	}
+
+	// replace a file in another directory, as when renaming a temporary file
+	// over its target
+	err = f.WriteFile("new.tmp", []byte("new"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.Rename("new.tmp", "dir/sub/c.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, s, "/share/dir/sub/c.md", "new")
+	if s.Exists("/share/new.tmp") {
+		t.Fatal("rename left the source behind")
+	}
+
+	// replace a file of the same name in another directory
+	s.WriteFile("/share/dir/sub/deep/hello.txt", "deep")
+	err = f.Rename("dir/hello.txt", "dir/sub/deep/hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, s, "/share/dir/sub/deep/hello.txt", "other")
+
+	// directories are moved but never replace or are replaced
+	err = f.Rename("dir/sub", "moved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, s, "/share/moved/deep/d.x", testFiles["/share/dir/sub/deep/d.x"])
+	err = f.Rename("big.bin", "empty")
+	if !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("got %v, want %v", err, fs.ErrExist)
+	}
+	err = f.Rename("moved", "dir/a.go")
+	if !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("got %v, want %v", err, fs.ErrExist)
+	}
+	assertContent(t, s, "/share/big.bin", testFiles["/share/big.bin"])
+	assertContent(t, s, "/share/dir/a.go", testFiles["/share/dir/a.go"])
+
+	err = f.Rename("missing", "dir/missing")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("got %v, want %v", err, fs.ErrNotExist)
+	}
+
+	for _, dir := range []string{".", "dir", "moved", "moved/deep"} {
+		assertNoTempFolders(t, f, dir)
+	}
+}
+
+func TestWritableFSRenameRollback(t *testing.T) {
+	tests := []struct {
+		name     string
+		newname  string
+		method   string
+		n        int
+		existing bool
+	}{
+		{"rename fails", "dir/renamed.txt", "SYNO.FileStation.Rename.rename", 1, false},
+		{"move fails", "dir/renamed.txt", "SYNO.FileStation.CopyMove.start", 2, false},
+		{"replace fails", "dir/a.go", "SYNO.FileStation.CopyMove.start", 2, true},
+		{"replace of same name fails", "dir/hello.txt", "SYNO.FileStation.CopyMove.start", 2, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestServer(t)
+			f := newTestWritableFS(t, s, "/share")
+			if test.existing {
+				s.WriteFile("/share/"+test.newname, "existing")
+			}
+
+			s.FailCall(test.method, test.n, 401)
+			err := f.Rename("hello.txt", test.newname)
+			if err == nil {
+				t.Fatal("expected the rename to fail")
+			}
+			assertContent(t, s, "/share/hello.txt", testFiles["/share/hello.txt"])
+			if test.existing {
+				assertContent(t, s, "/share/"+test.newname, "existing")
+			} else if s.Exists("/share/" + test.newname) {
+				t.Fatal("failed rename created the target")
+			}
+			assertNoTempFolders(t, f, ".")
+			assertNoTempFolders(t, f, "dir")
+		})
+	}
+}
@@ -4,6 +4,7 @@ go 1.18
 
 require (
 	github.com/google/uuid v1.6.0
+	github.com/spf13/afero v1.10.0
 	github.com/urfave/cli/v2 v2.27.7
 )
 
@@ -11,4 +12,5 @@ require (
 	github.com/cpuguy83/go-md2man/v2 v2.0.7 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/xrash/smetrics v0.0.0-20240521201337-686a1a2994c1 // indirect
+	golang.org/x/text v0.3.7 // indirect
 )
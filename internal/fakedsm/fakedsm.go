@@ -0,0 +1,879 @@
+// Package fakedsm implements an in-memory FileStation server for tests.
+package fakedsm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	DefaultMtime = time.Unix(1600000000, 0)
+)
+
+type node struct {
+	isDir   bool
+	content []byte
+	mtime   time.Time
+	posix   int
+}
+
+type search struct {
+	results []string
+	found   int
+	stopped bool
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nodes    map[string]*node
+	errors   map[string]int
+	calls    map[string]int
+	info     map[string]any
+	noRange  bool
+	badRange bool
+	pageSize int
+	truncate map[string]int
+	realPath map[string]string
+	session  int
+	fail     map[string][]int
+	taskId   int
+
+	searches   map[string]*search
+	searchStep int
+}
+
+// New starts a server holding files, keyed by absolute path. Keys ending in
+// "/" are directories. Top level directories are the shared folders.
+func New(files map[string]string) *Server {
+	s := &Server{
+		nodes: map[string]*node{
+			"/": {isDir: true, mtime: DefaultMtime},
+		},
+		errors:   make(map[string]int),
+		calls:    make(map[string]int),
+		truncate: make(map[string]int),
+		realPath: make(map[string]string),
+		searches: make(map[string]*search),
+		fail:     make(map[string][]int),
+		info: map[string]any{
+			"hostname":        "fakedsm",
+			"is_manager":      true,
+			"support_sharing": true,
+			"uid":             1024,
+			"gid":             100,
+		},
+	}
+	for p, content := range files {
+		if strings.HasSuffix(p, "/") {
+			s.Mkdir(p)
+		} else {
+			s.WriteFile(p, content)
+		}
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// SetNoRange makes downloads ignore Range headers.
+func (s *Server) SetNoRange(noRange bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.noRange = noRange
+}
+
+// SetPageSize caps the number of entries returned by a single list call.
+func (s *Server) SetPageSize(pageSize int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = pageSize
+}
+
+// SetInfo replaces the data returned by SYNO.FileStation.Info. A nil value
+// makes the API unavailable.
+func (s *Server) SetInfo(info map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.info = info
+}
+
+// SetSessionError makes every request other than SYNO.API.Info fail with
+// code, e.g. 119 for an expired session.
+func (s *Server) SetSessionError(code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session = code
+}
+
+// SetBadRange makes ranged downloads return the whole file as a partial
+// response starting at byte 0, regardless of the requested range.
+func (s *Server) SetBadRange(badRange bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.badRange = badRange
+}
+
+// FailCall makes the nth next request to apiMethod, e.g.
+// "SYNO.FileStation.Rename.rename", fail with code.
+func (s *Server) FailCall(apiMethod string, n int, code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	codes := s.fail[apiMethod]
+	for len(codes) < n {
+		codes = append(codes, 0)
+	}
+	codes[n-1] = code
+	s.fail[apiMethod] = codes
+}
+
+// SetTruncate makes downloads of p drop the connection after n bytes. A
+// negative n restores complete downloads.
+func (s *Server) SetTruncate(p string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 0 {
+		delete(s.truncate, path.Clean(p))
+		return
+	}
+	s.truncate[path.Clean(p)] = n
+}
+
+// SetSearchStep makes each search list call find at most n more results, so
+// that searches finish after several calls. A negative n makes searches never
+// finish, and zero, the default, finds all results immediately.
+func (s *Server) SetSearchStep(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searchStep = n
+}
+
+// Searches returns the ids of search tasks that have not been cleaned.
+func (s *Server) Searches() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var taskIds []string
+	for taskId := range s.searches {
+		taskIds = append(taskIds, taskId)
+	}
+	sort.Strings(taskIds)
+	return taskIds
+}
+
+// SetMtime changes the modification time of p.
+func (s *Server) SetMtime(p string, mtime time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n, ok := s.nodes[path.Clean(p)]; ok {
+		n.mtime = mtime
+	}
+}
+
+// SetError makes any request touching p or a path below it fail with code.
+func (s *Server) SetError(p string, code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors[path.Clean(p)] = code
+}
+
+// SetRealPath overrides the real_path reported for p, which defaults to p
+// below /volume1.
+func (s *Server) SetRealPath(p string, realPath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.realPath[path.Clean(p)] = realPath
+}
+
+func (s *Server) Mkdir(p string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(path.Clean(p), &node{isDir: true, mtime: DefaultMtime})
+}
+
+func (s *Server) WriteFile(p string, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(path.Clean(p), &node{content: []byte(content), mtime: DefaultMtime})
+}
+
+func (s *Server) ReadFile(p string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.nodes[path.Clean(p)]
+	if !ok || n.isDir {
+		return "", false
+	}
+	return string(n.content), true
+}
+
+func (s *Server) Mtime(p string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.nodes[path.Clean(p)]
+	if !ok {
+		return time.Time{}, false
+	}
+	return n.mtime, true
+}
+
+func (s *Server) Exists(p string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.nodes[path.Clean(p)]
+	return ok
+}
+
+// Calls returns the number of requests made to the given API method, e.g.
+// "SYNO.FileStation.List.getinfo".
+func (s *Server) Calls(apiMethod string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[apiMethod]
+}
+
+func (s *Server) put(p string, n *node) {
+	for dir := path.Dir(p); dir != "/"; dir = path.Dir(dir) {
+		if s.nodes[dir] == nil {
+			s.nodes[dir] = &node{isDir: true, mtime: DefaultMtime}
+		}
+	}
+	s.nodes[p] = n
+}
+
+func (s *Server) children(dir string) []string {
+	var children []string
+	for p := range s.nodes {
+		if p != "/" && path.Dir(p) == dir {
+			children = append(children, p)
+		}
+	}
+	sort.Strings(children)
+	return children
+}
+
+func (s *Server) errorCode(paths ...string) int {
+	for _, p := range paths {
+		for prefix, code := range s.errors {
+			if p == prefix || strings.HasPrefix(p, prefix+"/") {
+				return code
+			}
+		}
+	}
+	return 0
+}
+
+func (s *Server) fileJSON(p string, n *node) map[string]any {
+	posix := n.posix
+	if posix == 0 {
+		posix = 755
+	}
+	realPath, ok := s.realPath[p]
+	if !ok {
+		realPath = "/volume1" + p
+	}
+	return map[string]any{
+		"path":  p,
+		"name":  path.Base(p),
+		"isdir": n.isDir,
+		"additional": map[string]any{
+			"real_path": realPath,
+			"size":      len(n.content),
+			"time": map[string]any{
+				"mtime": n.mtime.Unix(),
+			},
+			"perm": map[string]any{
+				"posix":       posix,
+				"is_acl_mode": false,
+			},
+			"owner": map[string]any{
+				"user":  "admin",
+				"group": "users",
+				"uid":   1024,
+				"gid":   100,
+			},
+			"type": strings.ToUpper(strings.TrimPrefix(path.Ext(p), ".")),
+		},
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var formErr error
+	if r.Method == http.MethodPost {
+		// read the whole body before taking the lock, uploads may be slow
+		formErr = r.ParseMultipartForm(32 << 20)
+		if formErr == nil {
+			for k, v := range r.MultipartForm.Value {
+				q[k] = v
+			}
+		}
+	}
+	apiMethod := q.Get("api") + "." + q.Get("method")
+
+	s.mu.Lock()
+	s.calls[apiMethod]++
+	session := s.session
+	failCode := 0
+	if codes := s.fail[apiMethod]; len(codes) > 0 {
+		failCode = codes[0]
+		s.fail[apiMethod] = codes[1:]
+	}
+	s.mu.Unlock()
+
+	if session != 0 && q.Get("api") != "SYNO.API.Info" {
+		writeError(w, session)
+		return
+	}
+	if failCode != 0 {
+		writeError(w, failCode)
+		return
+	}
+
+	if apiMethod == "SYNO.FileStation.Download.download" {
+		s.download(w, r, q)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch apiMethod {
+	case "SYNO.API.Info.query":
+		s.query(w, q)
+	case "SYNO.FileStation.Info.get":
+		if s.info == nil {
+			writeError(w, 102)
+			return
+		}
+		writeData(w, s.info)
+	case "SYNO.FileStation.List.list_share":
+		s.listShare(w)
+	case "SYNO.FileStation.List.getinfo":
+		s.getInfo(w, q)
+	case "SYNO.FileStation.List.list":
+		s.list(w, q)
+	case "SYNO.FileStation.Upload.upload":
+		if formErr != nil {
+			// the client aborted the upload, nothing is saved
+			writeError(w, 1800)
+			return
+		}
+		s.upload(w, r, q)
+	case "SYNO.FileStation.CreateFolder.create":
+		s.createFolder(w, q)
+	case "SYNO.FileStation.Delete.delete":
+		s.delete(w, q)
+	case "SYNO.FileStation.Rename.rename":
+		s.rename(w, q)
+	case "SYNO.FileStation.CopyMove.start":
+		s.copyMove(w, q)
+	case "SYNO.FileStation.CopyMove.status":
+		writeData(w, map[string]any{
+			"finished": true,
+			"progress": 1,
+		})
+	case "SYNO.FileStation.Search.start":
+		s.searchStart(w, q)
+	case "SYNO.FileStation.Search.list":
+		s.searchList(w, q)
+	case "SYNO.FileStation.Search.stop":
+		for _, taskId := range jsonStrings(q["taskid"]) {
+			if search, ok := s.searches[taskId]; ok {
+				search.stopped = true
+			}
+		}
+		writeData(w, map[string]any{})
+	case "SYNO.FileStation.Search.clean":
+		for _, taskId := range jsonStrings(q["taskid"]) {
+			delete(s.searches, taskId)
+		}
+		writeData(w, map[string]any{})
+	default:
+		writeError(w, 103)
+	}
+}
+
+func (s *Server) query(w http.ResponseWriter, q map[string][]string) {
+	descriptions := make(map[string]any)
+	for _, name := range strings.Split(strings.Join(q["query"], ","), ",") {
+		if name == "SYNO.FileStation.Info" && s.info == nil {
+			continue
+		}
+		descriptions[name] = map[string]any{
+			"path":       "entry.cgi",
+			"minVersion": 1,
+			"maxVersion": 3,
+		}
+	}
+	writeData(w, descriptions)
+}
+
+func (s *Server) listShare(w http.ResponseWriter) {
+	shares := make([]any, 0)
+	for _, p := range s.children("/") {
+		if s.errorCode(p) != 0 {
+			continue
+		}
+		shares = append(shares, map[string]any{
+			"path":  p,
+			"name":  path.Base(p),
+			"isdir": true,
+			"additional": map[string]any{
+				"real_path": "/volume1" + p,
+				"time": map[string]any{
+					"mtime": s.nodes[p].mtime.Unix(),
+				},
+				"perm": map[string]any{
+					"posix":       777,
+					"is_acl_mode": false,
+				},
+				"volume_status": map[string]any{
+					"freespace":  1 << 30,
+					"totalspace": 1 << 40,
+				},
+			},
+		})
+	}
+	writeData(w, map[string]any{
+		"total":  len(shares),
+		"offset": 0,
+		"shares": shares,
+	})
+}
+
+func (s *Server) getInfo(w http.ResponseWriter, q map[string][]string) {
+	paths := jsonStrings(q["path"])
+	if code := s.errorCode(paths...); code != 0 {
+		writeError(w, code)
+		return
+	}
+	files := make([]any, 0)
+	for _, p := range paths {
+		n := s.nodes[path.Clean(p)]
+		if n == nil {
+			files = append(files, map[string]any{
+				"path": p,
+				"name": path.Base(p),
+				"code": 408,
+			})
+			continue
+		}
+		files = append(files, s.fileJSON(path.Clean(p), n))
+	}
+	writeData(w, map[string]any{
+		"files": files,
+	})
+}
+
+func (s *Server) list(w http.ResponseWriter, q map[string][]string) {
+	dir := path.Clean(first(q["folder_path"]))
+	if code := s.errorCode(dir); code != 0 {
+		writeError(w, code)
+		return
+	}
+	n := s.nodes[dir]
+	if n == nil || !n.isDir {
+		writeError(w, 408)
+		return
+	}
+	patterns := strings.Split(first(q["pattern"]), ",")
+	files := make([]any, 0)
+	for _, p := range s.children(dir) {
+		if !matchAny(patterns, path.Base(p)) {
+			continue
+		}
+		files = append(files, s.fileJSON(p, s.nodes[p]))
+	}
+	total := len(files)
+	offset, _ := strconv.Atoi(first(q["offset"]))
+	limit, _ := strconv.Atoi(first(q["limit"]))
+	if s.pageSize > 0 && (limit <= 0 || limit > s.pageSize) {
+		limit = s.pageSize
+	}
+	if offset > len(files) {
+		offset = len(files)
+	}
+	files = files[offset:]
+	if limit > 0 && limit < len(files) {
+		files = files[:limit]
+	}
+	writeData(w, map[string]any{
+		"total":  total,
+		"offset": offset,
+		"files":  files,
+	})
+}
+
+func (s *Server) download(w http.ResponseWriter, r *http.Request, q map[string][]string) {
+	paths := jsonStrings(q["path"])
+	if len(paths) != 1 {
+		writeError(w, 401)
+		return
+	}
+	p := path.Clean(paths[0])
+
+	s.mu.Lock()
+	code := s.errorCode(p)
+	n := s.nodes[p]
+	noRange := s.noRange
+	badRange := s.badRange
+	truncate, truncated := s.truncate[p]
+	s.mu.Unlock()
+
+	if code != 0 {
+		writeError(w, code)
+		return
+	}
+	if n == nil || n.isDir {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	content := n.content
+	if truncated {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		_, _ = w.Write(content[:truncate])
+		return
+	}
+	if noRange {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		_, _ = w.Write(content)
+		return
+	}
+	if badRange && r.Header.Get("Range") != "" {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(content)-1, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(content)
+		return
+	}
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+}
+
+func (s *Server) upload(w http.ResponseWriter, r *http.Request, q map[string][]string) {
+	dir := path.Clean(first(q["path"]))
+	if code := s.errorCode(dir); code != 0 {
+		writeError(w, code)
+		return
+	}
+	if s.nodes[dir] == nil {
+		if first(q["create_parents"]) != "true" {
+			writeError(w, 408)
+			return
+		}
+		s.put(dir, &node{isDir: true, mtime: time.Now()})
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, 401)
+		return
+	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, 1800)
+		return
+	}
+	p := path.Join(dir, header.Filename)
+	if existing := s.nodes[p]; existing != nil {
+		switch {
+		case existing.isDir:
+			writeError(w, 414)
+			return
+		case first(q["overwrite"]) == "false":
+			writeData(w, map[string]any{
+				"blSkip": true,
+				"file":   header.Filename,
+			})
+			return
+		case first(q["overwrite"]) != "true":
+			writeError(w, 414)
+			return
+		}
+	}
+	mtime := time.Now()
+	if value := first(q["mtime"]); value != "" {
+		ms, _ := strconv.ParseInt(value, 10, 64)
+		mtime = time.UnixMilli(ms)
+	}
+	s.put(p, &node{content: content, mtime: mtime})
+	writeData(w, map[string]any{
+		"blSkip": false,
+		"file":   header.Filename,
+	})
+}
+
+func (s *Server) createFolder(w http.ResponseWriter, q map[string][]string) {
+	parents := jsonStrings(q["folder_path"])
+	names := jsonStrings(q["name"])
+	forceParent := first(q["force_parent"]) == "true"
+	folders := make([]any, 0)
+	for i := range parents {
+		parent := path.Clean(parents[i])
+		p := path.Join(parent, names[i])
+		if code := s.errorCode(p); code != 0 {
+			writeError(w, code)
+			return
+		}
+		if s.nodes[parent] == nil && !forceParent {
+			writeError(w, 408)
+			return
+		}
+		if existing := s.nodes[p]; existing != nil {
+			if !existing.isDir || !forceParent {
+				writeError(w, 414)
+				return
+			}
+		} else {
+			s.put(p, &node{isDir: true, mtime: time.Now()})
+		}
+		folders = append(folders, s.fileJSON(p, s.nodes[p]))
+	}
+	writeData(w, map[string]any{
+		"folders": folders,
+	})
+}
+
+func (s *Server) delete(w http.ResponseWriter, q map[string][]string) {
+	paths := jsonStrings(q["path"])
+	if code := s.errorCode(paths...); code != 0 {
+		writeError(w, code)
+		return
+	}
+	for _, p := range paths {
+		p = path.Clean(p)
+		if s.nodes[p] == nil {
+			writeError(w, 408)
+			return
+		}
+		if len(s.children(p)) > 0 && first(q["recursive"]) == "false" {
+			writeError(w, 900)
+			return
+		}
+		s.move(p, "", false)
+	}
+	writeData(w, nil)
+}
+
+func (s *Server) rename(w http.ResponseWriter, q map[string][]string) {
+	p := path.Clean(first(jsonStrings(q["path"])))
+	name := first(jsonStrings(q["name"]))
+	newPath := path.Join(path.Dir(p), name)
+	if code := s.errorCode(p, newPath); code != 0 {
+		writeError(w, code)
+		return
+	}
+	if s.nodes[p] == nil {
+		writeError(w, 408)
+		return
+	}
+	if s.nodes[newPath] != nil {
+		writeError(w, 414)
+		return
+	}
+	s.move(p, newPath, false)
+	writeData(w, map[string]any{
+		"files": []any{s.fileJSON(newPath, s.nodes[newPath])},
+	})
+}
+
+func (s *Server) copyMove(w http.ResponseWriter, q map[string][]string) {
+	dest := path.Clean(first(q["dest_folder_path"]))
+	paths := jsonStrings(q["path"])
+	if code := s.errorCode(append(paths, dest)...); code != 0 {
+		writeError(w, code)
+		return
+	}
+	if s.nodes[dest] == nil {
+		writeError(w, 408)
+		return
+	}
+	for _, p := range paths {
+		p = path.Clean(p)
+		if s.nodes[p] == nil {
+			writeError(w, 408)
+			return
+		}
+		newPath := path.Join(dest, path.Base(p))
+		if s.nodes[newPath] != nil {
+			if first(q["overwrite"]) != "true" {
+				writeError(w, 414)
+				return
+			}
+			s.move(newPath, "", false)
+		}
+		s.move(p, newPath, first(q["remove_src"]) != "true")
+	}
+	s.taskId++
+	writeData(w, map[string]any{
+		"taskid": "FileStation_" + strconv.Itoa(s.taskId),
+	})
+}
+
+// searchStart finds files below folder_path whose name contains pattern,
+// ignoring case, or matches it if it is a glob pattern.
+func (s *Server) searchStart(w http.ResponseWriter, q map[string][]string) {
+	folders := jsonStrings(q["folder_path"])
+	if code := s.errorCode(folders...); code != 0 {
+		writeError(w, code)
+		return
+	}
+	recursive := first(q["recursive"]) != "false"
+	pattern := strings.ToLower(first(q["pattern"]))
+	var extensions []string
+	for _, extension := range jsonStrings(q["extension"]) {
+		extensions = append(extensions, "*."+extension)
+	}
+
+	search := &search{}
+	var walk func(dir string)
+	walk = func(dir string) {
+		for _, p := range s.children(dir) {
+			name := strings.ToLower(path.Base(p))
+			matched := strings.Contains(name, pattern)
+			if strings.ContainsAny(pattern, "*?[") {
+				matched, _ = path.Match(pattern, name)
+			}
+			if matched && (len(extensions) == 0 || matchAny(extensions, name)) {
+				search.results = append(search.results, p)
+			}
+			if recursive && s.nodes[p].isDir {
+				walk(p)
+			}
+		}
+	}
+	for _, folder := range folders {
+		folder = path.Clean(folder)
+		if s.nodes[folder] == nil {
+			writeError(w, 408)
+			return
+		}
+		walk(folder)
+	}
+	if s.searchStep == 0 {
+		search.found = len(search.results)
+	}
+
+	s.taskId++
+	taskId := "FileStation_" + strconv.Itoa(s.taskId)
+	s.searches[taskId] = search
+	writeData(w, map[string]any{
+		"taskid": taskId,
+	})
+}
+
+func (s *Server) searchList(w http.ResponseWriter, q map[string][]string) {
+	search, ok := s.searches[first(q["taskid"])]
+	if !ok {
+		writeError(w, 599)
+		return
+	}
+	if !search.stopped && s.searchStep > 0 {
+		search.found += s.searchStep
+		if search.found > len(search.results) {
+			search.found = len(search.results)
+		}
+	}
+	finished := search.stopped || (search.found == len(search.results) && s.searchStep >= 0)
+
+	offset, _ := strconv.Atoi(first(q["offset"]))
+	limit, _ := strconv.Atoi(first(q["limit"]))
+	if offset > search.found {
+		offset = search.found
+	}
+	end := search.found
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	files := make([]any, 0)
+	for _, p := range search.results[offset:end] {
+		files = append(files, s.fileJSON(p, s.nodes[p]))
+	}
+	writeData(w, map[string]any{
+		"total":    search.found,
+		"offset":   offset,
+		"finished": finished,
+		"files":    files,
+	})
+}
+
+// move moves p and everything below it to newPath, or deletes it if newPath
+// is empty.
+func (s *Server) move(p string, newPath string, keep bool) {
+	moved := make(map[string]*node)
+	for k, n := range s.nodes {
+		if k == p || strings.HasPrefix(k, p+"/") {
+			moved[k] = n
+		}
+	}
+	for k, n := range moved {
+		if !keep {
+			delete(s.nodes, k)
+		}
+		if newPath != "" {
+			copied := *n
+			s.nodes[newPath+strings.TrimPrefix(k, p)] = &copied
+		}
+	}
+}
+
+// ----
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func jsonStrings(values []string) []string {
+	var result []string
+	for _, value := range values {
+		var decoded []string
+		if json.Unmarshal([]byte(value), &decoded) == nil {
+			result = append(result, decoded...)
+		} else {
+			result = append(result, strings.Split(value, ",")...)
+		}
+	}
+	return result
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return true
+		}
+		matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name))
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"success": true,
+		"data":    data,
+	})
+}
+
+func writeError(w http.ResponseWriter, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"success": false,
+		"error": map[string]any{
+			"code": code,
+		},
+	})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doCompress(cCtx *cli.Context) error {
+	if cCtx.NArg() < 2 {
+		return fmt.Errorf("usage: compress <path>... <dest file>")
+	}
+	args := cCtx.Args().Slice()
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		task, err := fileStationApi.Compress(filestation.CompressRequest{
+			Path:         args[:len(args)-1],
+			DestFilePath: args[len(args)-1],
+		})
+		if err != nil {
+			return err
+		}
+
+		status, err := task.Wait(cCtx.Context)
+		if err != nil {
+			_ = task.Stop()
+			return err
+		}
+
+		err = dump(status)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func doExtract(cCtx *cli.Context) error {
+	if cCtx.NArg() != 2 {
+		return fmt.Errorf("usage: extract <archive> <dest folder>")
+	}
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		task, err := fileStationApi.Extract(filestation.ExtractRequest{
+			FilePath:       cCtx.Args().Get(0),
+			DestFolderPath: cCtx.Args().Get(1),
+			Overwrite:      overwriteFlag.Get(cCtx),
+			KeepDir:        true,
+		})
+		if err != nil {
+			return err
+		}
+
+		status, err := task.Wait(cCtx.Context)
+		if err != nil {
+			_ = task.Stop()
+			return err
+		}
+
+		err = dump(status)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func doListArchive(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		extractListResponse, err := fileStationApi.ExtractList(filestation.ExtractListRequest{
+			FilePath: cCtx.Args().First(),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dump(extractListResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doListBackgroundTask(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		backgroundTaskListResponse, err := fileStationApi.BackgroundTaskList(filestation.BackgroundTaskListRequest{
+			ApiFilter: cCtx.Args().Slice(),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dump(backgroundTaskListResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func doClearBackgroundTask(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		backgroundTaskClearFinishedResponse, err := fileStationApi.BackgroundTaskClearFinished(filestation.BackgroundTaskClearFinishedRequest{
+			TaskId: cCtx.Args().Slice(),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dump(backgroundTaskClearFinishedResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
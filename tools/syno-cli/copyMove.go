@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doCopy(cCtx *cli.Context) error {
+	return doCopyMove(cCtx, false)
+}
+
+func doMove(cCtx *cli.Context) error {
+	return doCopyMove(cCtx, true)
+}
+
+func doCopyMove(cCtx *cli.Context, removeSrc bool) error {
+	if cCtx.NArg() < 2 {
+		return fmt.Errorf("usage: %s <path>... <dest folder>", cCtx.Command.Name)
+	}
+	args := cCtx.Args().Slice()
+	overwrite := filestation.OverwriteError
+	if overwriteFlag.Get(cCtx) {
+		overwrite = filestation.OverwriteOverwrite
+	}
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		task, err := fileStationApi.CopyMove(filestation.CopyMoveRequest{
+			Path:           args[:len(args)-1],
+			DestFolderPath: args[len(args)-1],
+			Overwrite:      overwrite,
+			RemoveSrc:      removeSrc,
+		})
+		if err != nil {
+			return err
+		}
+
+		status, err := task.Wait(cCtx.Context)
+		if err != nil {
+			return err
+		}
+
+		err = dump(status)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
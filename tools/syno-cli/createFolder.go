@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+	"path"
+)
+
+func doCreateFolder(cCtx *cli.Context) error {
+	if cCtx.NArg() < 1 {
+		return fmt.Errorf("usage: create-folder <path>...")
+	}
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		var folderPaths []string
+		var names []string
+		for _, p := range cCtx.Args().Slice() {
+			folderPaths = append(folderPaths, path.Dir(p))
+			names = append(names, path.Base(p))
+		}
+
+		createFolderResponse, err := fileStationApi.CreateFolder(filestation.CreateFolderRequest{
+			FolderPath:  folderPaths,
+			Name:        names,
+			ForceParent: createParentsFlag.Get(cCtx),
+			Additional:  []filestation.AdditionalField{filestation.AdditionalSize, filestation.AdditionalTime},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dump(createFolderResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doDelete(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		deleteResponse, err := fileStationApi.Delete(filestation.DeleteRequest{
+			Path:      cCtx.Args().Slice(),
+			Recursive: deleteRecursiveFlag.Get(cCtx),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dump(deleteResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doDirSize(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		task, err := fileStationApi.DirSize(filestation.DirSizeRequest{
+			Path: cCtx.Args().Slice(),
+		})
+		if err != nil {
+			return err
+		}
+
+		status, err := task.Wait(cCtx.Context)
+		if err != nil {
+			_ = task.Stop()
+			return err
+		}
+
+		err = dump(status)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -13,9 +13,13 @@ func doDownload(cCtx *cli.Context) error {
 	return withClient(cCtx, func(c *api.Client) error {
 		fileStationApi := filestation.New(c)
 
+		if resumeFlag.Get(cCtx) && cCtx.NArg() == 1 {
+			return fileStationApi.DownloadFile(cCtx.Args().First(), filepath.Base(cCtx.Args().First()))
+		}
+
 		r, err := fileStationApi.Download(filestation.DownloadRequest{
 			Path: cCtx.Args().Slice(),
-			Mode: "download",
+			Mode: filestation.DownloadModeDownload,
 		})
 		if err != nil {
 			return err
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doListFavorite(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		favoriteListResponse, err := fileStationApi.FavoriteList(filestation.FavoriteListRequest{
+			Additional: []filestation.AdditionalField{filestation.AdditionalRealPath, filestation.AdditionalMountPointType},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dump(favoriteListResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -12,7 +12,7 @@ func doGetInfo(cCtx *cli.Context) error {
 
 		getInfoResponse, err := fileStationApi.GetInfo(filestation.GetInfoRequest{
 			Path:       cCtx.Args().Slice(),
-			Additional: []string{"size", "time"},
+			Additional: []filestation.AdditionalField{filestation.AdditionalSize, filestation.AdditionalTime},
 		})
 		if err != nil {
 			return err
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doInfo(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		infoResponse, err := fileStationApi.Info()
+		if err != nil {
+			return err
+		}
+
+		err = dump(infoResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -12,7 +12,7 @@ func doList(cCtx *cli.Context) error {
 
 		listResponse, err := fileStationApi.List(filestation.ListRequest{
 			FolderPath: cCtx.Args().First(),
-			Additional: []string{"size", "time"},
+			Additional: []filestation.AdditionalField{filestation.AdditionalSize, filestation.AdditionalTime},
 		})
 		if err != nil {
 			return err
@@ -22,6 +22,48 @@ var (
 		Usage:   "password",
 		EnvVars: []string{"SYNOLOGY_PASSWORD"},
 	}
+	createParentsFlag = &cli.BoolFlag{
+		Name:  "create-parents",
+		Usage: "create parent folders",
+	}
+	overwriteFlag = &cli.BoolFlag{
+		Name:  "overwrite",
+		Usage: "overwrite existing files",
+	}
+	recursiveFlag = &cli.BoolFlag{
+		Name:  "recursive",
+		Usage: "recursive",
+		Value: true,
+	}
+	deleteRecursiveFlag = &cli.BoolFlag{
+		Name:  "recursive",
+		Usage: "delete folders and their contents",
+	}
+	patternFlag = &cli.StringFlag{
+		Name:  "pattern",
+		Usage: "glob pattern",
+	}
+	sizeFlag = &cli.StringFlag{
+		Name:  "size",
+		Usage: "thumbnail size (small, medium, large, original)",
+		Value: "small",
+	}
+	resumeFlag = &cli.BoolFlag{
+		Name:  "resume",
+		Usage: "resume partial download",
+	}
+	sharePasswordFlag = &cli.StringFlag{
+		Name:  "share-password",
+		Usage: "share link password",
+	}
+	expiresInFlag = &cli.DurationFlag{
+		Name:  "expires-in",
+		Usage: "share link expiry",
+	}
+	extensionFlag = &cli.StringSliceFlag{
+		Name:  "extension",
+		Usage: "file extension",
+	}
 
 	app = &cli.App{
 		Name:  "syno-cli",
@@ -32,6 +74,11 @@ var (
 			passwordFlag,
 		},
 		Commands: []*cli.Command{
+			{
+				Name:   "info",
+				Usage:  "info",
+				Action: doInfo,
+			},
 			{
 				Name:   "list-share",
 				Usage:  "list share",
@@ -48,10 +95,150 @@ var (
 				Action: doGetInfo,
 			},
 			{
-				Name:   "download",
-				Usage:  "download",
+				Name:  "download",
+				Usage: "download",
+				Flags: []cli.Flag{
+					resumeFlag,
+				},
 				Action: doDownload,
 			},
+			{
+				Name:  "upload",
+				Usage: "upload",
+				Flags: []cli.Flag{
+					createParentsFlag,
+					overwriteFlag,
+				},
+				Action: doUpload,
+			},
+			{
+				Name:  "create-folder",
+				Usage: "create folder",
+				Flags: []cli.Flag{
+					createParentsFlag,
+				},
+				Action: doCreateFolder,
+			},
+			{
+				Name:   "rename",
+				Usage:  "rename",
+				Action: doRename,
+			},
+			{
+				Name:  "delete",
+				Usage: "delete",
+				Flags: []cli.Flag{
+					deleteRecursiveFlag,
+				},
+				Action: doDelete,
+			},
+			{
+				Name:  "copy",
+				Usage: "copy",
+				Flags: []cli.Flag{
+					overwriteFlag,
+				},
+				Action: doCopy,
+			},
+			{
+				Name:  "move",
+				Usage: "move",
+				Flags: []cli.Flag{
+					overwriteFlag,
+				},
+				Action: doMove,
+			},
+			{
+				Name:  "search",
+				Usage: "search",
+				Flags: []cli.Flag{
+					recursiveFlag,
+					patternFlag,
+					extensionFlag,
+				},
+				Action: doSearch,
+			},
+			{
+				Name:   "dir-size",
+				Usage:  "directory size",
+				Action: doDirSize,
+			},
+			{
+				Name:   "md5",
+				Usage:  "MD5 checksum",
+				Action: doMD5,
+			},
+			{
+				Name:   "compress",
+				Usage:  "compress",
+				Action: doCompress,
+			},
+			{
+				Name:  "extract",
+				Usage: "extract",
+				Flags: []cli.Flag{
+					overwriteFlag,
+				},
+				Action: doExtract,
+			},
+			{
+				Name:   "list-archive",
+				Usage:  "list archive",
+				Action: doListArchive,
+			},
+			{
+				Name:  "thumb",
+				Usage: "thumbnail",
+				Flags: []cli.Flag{
+					sizeFlag,
+				},
+				Action: doThumb,
+			},
+			{
+				Name:   "list-favorite",
+				Usage:  "list favorites",
+				Action: doListFavorite,
+			},
+			{
+				Name:   "list-virtual-folder",
+				Usage:  "list virtual folders",
+				Action: doListVirtualFolder,
+			},
+			{
+				Name:   "list-background-task",
+				Usage:  "list background tasks",
+				Action: doListBackgroundTask,
+			},
+			{
+				Name:   "clear-background-task",
+				Usage:  "clear finished background tasks",
+				Action: doClearBackgroundTask,
+			},
+			{
+				Name:  "share-link",
+				Usage: "share link",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "create",
+						Usage: "create",
+						Flags: []cli.Flag{
+							sharePasswordFlag,
+							expiresInFlag,
+						},
+						Action: doShareLinkCreate,
+					},
+					{
+						Name:   "list",
+						Usage:  "list",
+						Action: doShareLinkList,
+					},
+					{
+						Name:   "delete",
+						Usage:  "delete",
+						Action: doShareLinkDelete,
+					},
+				},
+			},
 		},
 	}
 )
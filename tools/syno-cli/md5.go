@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doMD5(cCtx *cli.Context) error {
+	if cCtx.NArg() < 1 || cCtx.NArg() > 2 {
+		return fmt.Errorf("usage: md5 <path> [local file]")
+	}
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		if cCtx.NArg() == 2 {
+			ok, err := fileStationApi.VerifyMD5File(cCtx.Context, cCtx.Args().Get(0), cCtx.Args().Get(1))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("MD5 mismatch")
+			}
+			return nil
+		}
+
+		task, err := fileStationApi.MD5(filestation.MD5Request{
+			FilePath: cCtx.Args().First(),
+		})
+		if err != nil {
+			return err
+		}
+
+		status, err := task.Wait(cCtx.Context)
+		if err != nil {
+			_ = task.Stop()
+			return err
+		}
+
+		err = dump(status)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doRename(cCtx *cli.Context) error {
+	if cCtx.NArg() != 2 {
+		return fmt.Errorf("usage: rename <path> <new name>")
+	}
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		renameResponse, err := fileStationApi.Rename(filestation.RenameRequest{
+			Path:       []string{cCtx.Args().Get(0)},
+			Name:       []string{cCtx.Args().Get(1)},
+			Additional: []filestation.AdditionalField{filestation.AdditionalSize, filestation.AdditionalTime},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dump(renameResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doSearch(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		it, err := fileStationApi.Search(cCtx.Context, filestation.SearchRequest{
+			SearchStartRequest: filestation.SearchStartRequest{
+				FolderPath: cCtx.Args().Slice(),
+				Recursive:  recursiveFlag.Get(cCtx),
+				Pattern:    patternFlag.Get(cCtx),
+				Extension:  extensionFlag.Get(cCtx),
+			},
+			Additional: []filestation.AdditionalField{filestation.AdditionalSize, filestation.AdditionalTime},
+		})
+		if err != nil {
+			return err
+		}
+		defer func(it *filestation.SearchIterator) {
+			_ = it.Close()
+		}(it)
+
+		for it.Next() {
+			err = dump(it.File())
+			if err != nil {
+				return err
+			}
+		}
+		return it.Err()
+	})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+	"time"
+)
+
+func doShareLinkCreate(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		var dateExpired time.Time
+		if expiresInFlag.Get(cCtx) > 0 {
+			dateExpired = time.Now().Add(expiresInFlag.Get(cCtx))
+		}
+
+		sharingCreateResponse, err := fileStationApi.SharingCreate(filestation.SharingCreateRequest{
+			Path:        cCtx.Args().Slice(),
+			Password:    sharePasswordFlag.Get(cCtx),
+			DateExpired: dateExpired,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dump(sharingCreateResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func doShareLinkList(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		sharingListResponse, err := fileStationApi.SharingList(filestation.SharingListRequest{})
+		if err != nil {
+			return err
+		}
+
+		err = dump(sharingListResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func doShareLinkDelete(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		sharingDeleteResponse, err := fileStationApi.SharingDelete(filestation.SharingDeleteRequest{
+			Id: cCtx.Args().Slice(),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dump(sharingDeleteResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
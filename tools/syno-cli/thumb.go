@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func doThumb(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		r, err := fileStationApi.Thumb(filestation.ThumbRequest{
+			Path: cCtx.Args().First(),
+			Size: filestation.ThumbSize(sizeFlag.Get(cCtx)),
+		})
+		if err != nil {
+			return err
+		}
+		defer func(r io.ReadCloser) {
+			_ = r.Close()
+		}(r)
+
+		name := strings.TrimSuffix(filepath.Base(cCtx.Args().First()), filepath.Ext(cCtx.Args().First())) + ".thumb"
+		extensions, _ := mime.ExtensionsByType(r.ContentType)
+		if len(extensions) > 0 {
+			name += extensions[0]
+		}
+
+		w, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		defer func(w io.WriteCloser) {
+			_ = w.Close()
+		}(w)
+
+		_, err = io.Copy(w, r)
+		return err
+	})
+}
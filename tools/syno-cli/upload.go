@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+	"os"
+	"path/filepath"
+)
+
+func doUpload(cCtx *cli.Context) error {
+	if cCtx.NArg() < 2 {
+		return fmt.Errorf("usage: upload <dest folder> <file>...")
+	}
+	destFolder := cCtx.Args().First()
+	overwrite := filestation.OverwriteError
+	if overwriteFlag.Get(cCtx) {
+		overwrite = filestation.OverwriteOverwrite
+	}
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		for _, localPath := range cCtx.Args().Tail() {
+			err := func() error {
+				f, err := os.Open(localPath)
+				if err != nil {
+					return err
+				}
+				defer func(f *os.File) {
+					_ = f.Close()
+				}(f)
+
+				fileInfo, err := f.Stat()
+				if err != nil {
+					return err
+				}
+
+				uploadResponse, err := fileStationApi.Upload(filestation.UploadRequest{
+					Path:            destFolder,
+					FileName:        filepath.Base(localPath),
+					Reader:          f,
+					CreateParents:   createParentsFlag.Get(cCtx),
+					Overwrite:       overwrite,
+					Mtime:           fileInfo.ModTime(),
+					CheckPermission: !createParentsFlag.Get(cCtx),
+				})
+				if err != nil {
+					return err
+				}
+
+				return dump(uploadResponse)
+			}()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/ngyewch/go-syno/api"
+	"github.com/ngyewch/go-syno/api/filestation"
+	"github.com/urfave/cli/v2"
+)
+
+func doListVirtualFolder(cCtx *cli.Context) error {
+	return withClient(cCtx, func(c *api.Client) error {
+		fileStationApi := filestation.New(c)
+
+		virtualFolderListResponse, err := fileStationApi.VirtualFolderList(filestation.VirtualFolderListRequest{
+			Type:       filestation.VirtualFolderType(cCtx.Args().First()),
+			Additional: []filestation.AdditionalField{filestation.AdditionalRealPath, filestation.AdditionalMountPointType, filestation.AdditionalVolumeStatus},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dump(virtualFolderListResponse)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}